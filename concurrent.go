@@ -0,0 +1,105 @@
+package orderedmap
+
+import (
+	"sync"
+)
+
+// ConcurrentOrderedMap is an OrderedMap that is safe for concurrent use by multiple goroutines.
+type ConcurrentOrderedMap[K comparable, V any] struct {
+	mutex sync.RWMutex
+	om    *OrderedMap[K,V]
+}
+
+// NewConcurrent creates a new ConcurrentOrderedMap.
+func NewConcurrent[K comparable, V any]() *ConcurrentOrderedMap[K,V] {
+	return &ConcurrentOrderedMap[K,V]{
+		om: New[K,V](),
+	}
+}
+
+// Get looks for the given key, and returns the value associated with it.
+// The boolean it returns says whether the key is present in the map.
+func (c *ConcurrentOrderedMap[K,V]) Get(key K) (V, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.om.Get(key)
+}
+
+// Set sets the key-value pair, and returns what `Get` would have returned
+// on that key prior to the call to `Set`.
+func (c *ConcurrentOrderedMap[K,V]) Set(key K, value V) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.om.Set(key, value)
+}
+
+// Delete removes the key-value pair, and returns what `Get` would have returned
+// on that key prior to the call to `Delete`.
+func (c *ConcurrentOrderedMap[K,V]) Delete(key K) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.om.Delete(key)
+}
+
+// CompareAndSwap atomically sets the value associated with the given key to newValue if its current
+// value is equal to oldValue according to eq, and returns whether it did so.
+func (c *ConcurrentOrderedMap[K,V]) CompareAndSwap(key K, oldValue, newValue V, eq func(V, V) bool) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.om.CompareAndSwap(key, oldValue, newValue, eq)
+}
+
+// CompareAndDelete atomically removes the pair with the given key if its current value is equal
+// to old according to eq, and returns whether it did so.
+func (c *ConcurrentOrderedMap[K,V]) CompareAndDelete(key K, old V, eq func(V, V) bool) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.om.CompareAndDelete(key, old, eq)
+}
+
+// Update atomically updates the value associated with the given key: f is called with the current
+// value, if any, and returns the new value and whether to keep it. If it says not to keep it,
+// the key is deleted. The write lock is held while f runs, so f must not call any of the map's methods.
+func (c *ConcurrentOrderedMap[K,V]) Update(key K, f func(old V, exists bool) (V, bool)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	old, exists := c.om.Get(key)
+	if value, keep := f(old, exists); keep {
+		c.om.Set(key, value)
+	} else if exists {
+		c.om.Delete(key)
+	}
+}
+
+// Len returns the length of the map.
+func (c *ConcurrentOrderedMap[K,V]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.om.Len()
+}
+
+// Keys returns the map's keys, from the oldest to the newest.
+func (c *ConcurrentOrderedMap[K,V]) Keys() []K {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.om.Keys()
+}
+
+// Range calls f on each pair, from the oldest to the newest, until f returns false.
+// The read lock is held for the whole iteration, so f sees a consistent view of the map,
+// but must not call any method that modifies it, as that would deadlock.
+func (c *ConcurrentOrderedMap[K,V]) Range(f func(key K, value V) bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	c.om.Range(f)
+}
+
+// Snapshot returns a shallow copy of the map, as an OrderedMap of its own, taken under the read lock.
+// Iterating over it, however long that takes, doesn't hold the lock; the other side of the coin is that
+// it doesn't reflect writes made to the map afterwards, nor do writes to it affect the map.
+func (c *ConcurrentOrderedMap[K,V]) Snapshot() *OrderedMap[K,V] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.om.Copy()
+}
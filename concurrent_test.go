@@ -0,0 +1,186 @@
+package orderedmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentBasicFeatures(t *testing.T) {
+	c := NewConcurrent[string, int]()
+
+	oldValue, present := c.Set("foo", 1)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+	c.Set("bar", 2)
+	c.Set("baz", 3)
+
+	oldValue, present = c.Set("foo", 4)
+	assert.Equal(t, 1, oldValue)
+	assert.True(t, present)
+
+	value, present := c.Get("foo")
+	assert.Equal(t, 4, value)
+	assert.True(t, present)
+
+	value, present = c.Delete("bar")
+	assert.Equal(t, 2, value)
+	assert.True(t, present)
+
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, []string{"foo", "baz"}, c.Keys())
+}
+
+func TestConcurrentRange(t *testing.T) {
+	c := NewConcurrent[int, int]()
+	for i := 0; i < 10; i++ {
+		c.Set(i, 2*i)
+	}
+
+	var keys []int
+	c.Range(func(key, value int) bool {
+		assert.Equal(t, 2*key, value)
+		keys = append(keys, key)
+		return key < 4
+	})
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, keys)
+}
+
+func TestConcurrentSnapshot(t *testing.T) {
+	c := NewConcurrent[string, int]()
+	c.Set("foo", 1)
+	c.Set("bar", 2)
+
+	snapshot := c.Snapshot()
+	assertOrderedPairsEqual[string, int](t, snapshot, []string{"foo", "bar"}, []int{1, 2})
+
+	// the snapshot and the map are independent
+	c.Set("baz", 3)
+	c.Delete("foo")
+	snapshot.Set("bar", 20)
+	assertOrderedPairsEqual[string, int](t, snapshot, []string{"foo", "bar"}, []int{1, 20})
+	assert.Equal(t, []string{"bar", "baz"}, c.Keys())
+	value, _ := c.Get("bar")
+	assert.Equal(t, 2, value)
+}
+
+func TestConcurrentUpdate(t *testing.T) {
+	c := NewConcurrent[string, int]()
+	increment := func(old int, _ bool) (int, bool) {
+		return old + 1, true
+	}
+
+	c.Update("foo", func(old int, exists bool) (int, bool) {
+		assert.Empty(t, old)
+		assert.False(t, exists)
+		return 12, true
+	})
+	c.Update("foo", func(old int, exists bool) (int, bool) {
+		assert.Equal(t, 12, old)
+		assert.True(t, exists)
+		return old, false
+	})
+	assert.Equal(t, 0, c.Len())
+
+	// not keeping an absent key is a no-op
+	c.Update("foo", func(int, bool) (int, bool) { return 0, false })
+	assert.Equal(t, 0, c.Len())
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Update("counter", increment)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, _ := c.Get("counter")
+	assert.Equal(t, 800, value)
+}
+
+func TestConcurrentCompareAndDelete(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	c := NewConcurrent[string, int]()
+	c.Set("foo", 1)
+
+	assert.False(t, c.CompareAndDelete("foo", 2, eq))
+	assert.Equal(t, 1, c.Len())
+
+	// only one of the concurrent callers gets to delete it
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	deleted := 0
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.CompareAndDelete("foo", 1, eq) {
+				mutex.Lock()
+				deleted++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestConcurrentCompareAndSwap(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	c := NewConcurrent[string, int]()
+	c.Set("counter", 0)
+
+	// optimistic increments, retrying on conflicts
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for {
+					current, _ := c.Get("counter")
+					if c.CompareAndSwap("counter", current, current+1, eq) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, _ := c.Get("counter")
+	assert.Equal(t, 800, value)
+	assert.False(t, c.CompareAndSwap("counter", 0, 1, eq))
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	c := NewConcurrent[int, int]()
+	n := 100
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				c.Set(g*n+i, i)
+				c.Get(i)
+				c.Range(func(int, int) bool { return true })
+				c.Snapshot()
+				if i%2 == 0 {
+					c.Delete(g*n + i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8*n/2, c.Len())
+}
@@ -0,0 +1,21 @@
+package orderedmap
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the ordered map to w as a two-column CSV, one `key,value` record per pair,
+// from the oldest to the newest. Fields are quoted as needed according to CSV rules.
+// It's a function rather than a method because it only applies to maps of strings.
+func WriteCSV[K, V ~string](om *OrderedMap[K,V], w io.Writer) error {
+	writer := csv.NewWriter(w)
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if err := writer.Write([]string{string(pair.Key), string(pair.Value)}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,32 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	om := New[string, string]()
+	om.Set("foo", "bar")
+	om.Set("with, comma", `with "quotes"`)
+	om.Set("multi\nline", "")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(om, &buf))
+	assert.Equal(t, "foo,bar\n\"with, comma\",\"with \"\"quotes\"\"\"\n\"multi\nline\",\n", buf.String())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"foo", "bar"}, {"with, comma", `with "quotes"`}, {"multi\nline", ""}}, records)
+
+	type name string
+	named := New[name, string]()
+	named.Set("foo", "bar")
+	buf.Reset()
+	require.NoError(t, WriteCSV(named, &buf))
+	assert.Equal(t, "foo,bar\n", buf.String())
+}
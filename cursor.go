@@ -0,0 +1,55 @@
+package orderedmap
+
+// Cursor points at one of an ordered map's pairs, and can be stepped towards either end of
+// the map. Once it steps past either end, or if it was created on an absent key, it's no
+// longer valid. The behaviour is undefined if the pair it points at gets removed from the map.
+type Cursor[K comparable, V any] struct {
+	pair *Pair[K,V]
+}
+
+// Seek returns a cursor pointing at the pair with the given key,
+// which is not valid if the key is not present in the map.
+func (om *OrderedMap[K,V]) Seek(key K) *Cursor[K,V] {
+	return &Cursor[K,V]{pair: om.GetPair(key)}
+}
+
+// Valid returns whether the cursor points at a pair.
+func (c *Cursor[K,V]) Valid() bool {
+	return c.pair != nil
+}
+
+// Next moves the cursor to the next pair, towards the newest one,
+// and returns whether it's still valid. It's a no-op on an invalid cursor.
+func (c *Cursor[K,V]) Next() bool {
+	if c.pair != nil {
+		c.pair = c.pair.Next()
+	}
+	return c.Valid()
+}
+
+// Prev moves the cursor to the previous pair, towards the oldest one,
+// and returns whether it's still valid. It's a no-op on an invalid cursor.
+func (c *Cursor[K,V]) Prev() bool {
+	if c.pair != nil {
+		c.pair = c.pair.Prev()
+	}
+	return c.Valid()
+}
+
+// Key returns the key of the pair the cursor points at, or K's zero value if it's not valid.
+func (c *Cursor[K,V]) Key() K {
+	if c.pair == nil {
+		var empty K
+		return empty
+	}
+	return c.pair.Key
+}
+
+// Value returns the value of the pair the cursor points at, or V's zero value if it's not valid.
+func (c *Cursor[K,V]) Value() V {
+	if c.pair == nil {
+		var empty V
+		return empty
+	}
+	return c.pair.Value
+}
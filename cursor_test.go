@@ -0,0 +1,49 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 5; i++ {
+		om.Set(i, 2*i)
+	}
+
+	c := om.Seek(2)
+	assert.True(t, c.Valid())
+	assert.Equal(t, 2, c.Key())
+	assert.Equal(t, 4, c.Value())
+
+	var keys []int
+	for ; c.Valid(); c.Next() {
+		assert.Equal(t, 2*c.Key(), c.Value())
+		keys = append(keys, c.Key())
+	}
+	assert.Equal(t, []int{2, 3, 4}, keys)
+
+	// stepping past the end invalidates it for good
+	assert.False(t, c.Prev())
+	assert.False(t, c.Next())
+	assert.Empty(t, c.Key())
+	assert.Empty(t, c.Value())
+
+	c = om.Seek(1)
+	assert.True(t, c.Next())
+	assert.Equal(t, 2, c.Key())
+	assert.True(t, c.Prev())
+	assert.True(t, c.Prev())
+	assert.Equal(t, 0, c.Key())
+	assert.False(t, c.Prev())
+
+	// follows the map's order
+	om.MoveToFront(3)
+	c = om.Seek(3)
+	assert.True(t, c.Next())
+	assert.Equal(t, 0, c.Key())
+
+	assert.False(t, om.Seek(12).Valid())
+	assert.False(t, New[int, int]().Seek(0).Valid())
+}
@@ -0,0 +1,47 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+var (
+	_ gob.GobEncoder = &OrderedMap[int,any]{}
+	_ gob.GobDecoder = &OrderedMap[int,any]{}
+)
+
+// gobPair is how pairs get serialized by gob, as it has no notion of our list.
+type gobPair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+// Pairs are encoded as a slice, from the oldest to the newest, so that their order is preserved.
+func (om *OrderedMap[K,V]) GobEncode() ([]byte, error) {
+	pairs := make([]gobPair[K,V], 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		pairs = append(pairs, gobPair[K,V]{Key: pair.Key, Value: pair.Value})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(pairs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// Pairs are set in the order in which they were encoded.
+func (om *OrderedMap[K,V]) GobDecode(data []byte) error {
+	var pairs []gobPair[K,V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&pairs); err != nil {
+		return err
+	}
+
+	om.initialize()
+	for _, pair := range pairs {
+		om.Set(pair.Key, pair.Value)
+	}
+	return nil
+}
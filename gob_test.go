@@ -0,0 +1,74 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGob(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		om := New[string, int]()
+		om.Set("z", 26)
+		om.Set("a", 1)
+		om.Set("m", 13)
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(om))
+
+		decoded := New[string, int]()
+		require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+		assertOrderedPairsEqual[string, int](t, decoded, []string{"z", "a", "m"}, []int{26, 1, 13})
+	})
+
+	t.Run("struct keys and values, into a zero value", func(t *testing.T) {
+		type point struct {
+			X, Y int
+		}
+		type wrapper struct {
+			Map *OrderedMap[point, []string]
+		}
+
+		om := New[point, []string]()
+		om.Set(point{1, 2}, []string{"foo"})
+		om.Set(point{0, 0}, nil)
+		om.Set(point{-1, 3}, []string{"bar", "baz"})
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(wrapper{om}))
+
+		var decoded wrapper
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+		if assert.NotNil(t, decoded.Map) {
+			assertLenEqual(t, decoded.Map, 3)
+			assertOrderedPairsEqual[point, []string](t, decoded.Map,
+				[]point{{1, 2}, {0, 0}, {-1, 3}},
+				[][]string{{"foo"}, nil, {"bar", "baz"}})
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		type wrapper struct {
+			Map OrderedMap[string, int]
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(&wrapper{}))
+
+		var decoded wrapper
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+		assertLenEqual(t, &decoded.Map, 0)
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(New[string, int]()))
+
+		decoded := New[string, int]()
+		require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+		assertLenEqual(t, decoded, 0)
+	})
+}
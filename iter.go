@@ -0,0 +1,149 @@
+package orderedmap
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Range calls f on each pair, from the oldest to the newest, until f returns false.
+// The behaviour is undefined if f modifies the map.
+func (om *OrderedMap[K,V]) Range(f func(key K, value V) bool) {
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if !f(pair.Key, pair.Value) {
+			return
+		}
+	}
+}
+
+// ForEachReverse calls f on each pair, from the newest to the oldest, until f returns false.
+// The behaviour is undefined if f modifies the map.
+func (om *OrderedMap[K,V]) ForEachReverse(f func(key K, value V) bool) {
+	for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+		if !f(pair.Key, pair.Value) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator over the ordered map's pairs, from the oldest to the newest, e.g.:
+// for key, value := range orderedMap.Iterator() { fmt.Printf("%v => %v\n", key, value) }
+func (om *OrderedMap[K,V]) Iterator() iter.Seq2[K,V] {
+	return func(yield func(K, V) bool) {
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Seq returns an iterator over the ordered map's pairs themselves, from the oldest to the newest, e.g.:
+// for pair := range orderedMap.Seq() { fmt.Printf("%v => %v\n", pair.Key, pair.Value) }
+func (om *OrderedMap[K,V]) Seq() iter.Seq[*Pair[K,V]] {
+	return func(yield func(*Pair[K,V]) bool) {
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair) {
+				return
+			}
+		}
+	}
+}
+
+// ReverseIterator returns an iterator over the ordered map's pairs, from the newest to the oldest, e.g.:
+// for key, value := range orderedMap.ReverseIterator() { fmt.Printf("%v => %v\n", key, value) }
+func (om *OrderedMap[K,V]) ReverseIterator() iter.Seq2[K,V] {
+	return func(yield func(K, V) bool) {
+		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq returns an iterator over the ordered map's keys, from the oldest to the newest.
+// Unlike Keys, it doesn't allocate a slice.
+func (om *OrderedMap[K,V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair.Key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns an iterator over the ordered map's values, from the oldest to the newest.
+// Unlike Values, it doesn't allocate a slice.
+func (om *OrderedMap[K,V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// From returns an iterator over the ordered map's pairs, starting at the given key (inclusive)
+// and up to the newest pair. It yields nothing if the key is not present.
+func (om *OrderedMap[K,V]) From(key K) iter.Seq2[K,V] {
+	return func(yield func(K, V) bool) {
+		for pair := om.GetPair(key); pair != nil; pair = pair.Next() {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns an iterator over the ordered map's pairs from the one with key `from` to the one
+// with key `to`, both inclusive, in the map's order. It yields nothing if either key is not present,
+// or if `from` comes after `to`; checking the latter means first walking from one to the other once
+// before yielding anything.
+func (om *OrderedMap[K,V]) Between(from, to K) iter.Seq2[K,V] {
+	return func(yield func(K, V) bool) {
+		first, last, present := om.getPairs(from, to)
+		if !present {
+			return
+		}
+
+		end := first
+		for end != nil && end != last {
+			end = end.Next()
+		}
+		if end == nil {
+			// from comes after to
+			return
+		}
+
+		for pair := first; ; pair = pair.Next() {
+			if !yield(pair.Key, pair.Value) || pair == last {
+				return
+			}
+		}
+	}
+}
+
+// Chunks returns an iterator over successive slices of up to size pairs, from the oldest to the
+// newest; only the last one may be shorter. Each slice is newly allocated, so can be kept; as with
+// GetPair, pairs removed from the map afterwards keep their key and value, but no longer link to it.
+// It panics if size isn't positive.
+func (om *OrderedMap[K,V]) Chunks(size int) iter.Seq[[]*Pair[K,V]] {
+	if size <= 0 {
+		panic(fmt.Sprintf("orderedmap: invalid chunk size %d, must be positive", size))
+	}
+
+	return func(yield func([]*Pair[K,V]) bool) {
+		pair := om.Oldest()
+		for pair != nil {
+			chunk := make([]*Pair[K,V], 0, min(size, om.Len()))
+			for ; pair != nil && len(chunk) < size; pair = pair.Next() {
+				chunk = append(chunk, pair)
+			}
+			if !yield(chunk) {
+				return
+			}
+		}
+	}
+}
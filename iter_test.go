@@ -0,0 +1,302 @@
+package orderedmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange(t *testing.T) {
+	om := New[string, int]()
+	om.Range(func(string, int) bool {
+		t.Fatal("shouldn't iterate over an empty map")
+		return true
+	})
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	var keys []string
+	var values []int
+	om.Range(func(key string, value int) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	assert.Equal(t, []string{"foo", "bar", "baz"}, keys)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	// stopping early
+	keys = nil
+	om.Range(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"foo", "bar"}, keys)
+}
+
+func TestForEachReverse(t *testing.T) {
+	om := New[string, int]()
+	om.ForEachReverse(func(string, int) bool {
+		t.Fatal("shouldn't iterate over an empty map")
+		return true
+	})
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	var keys []string
+	var values []int
+	om.ForEachReverse(func(key string, value int) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	assert.Equal(t, []string{"baz", "bar", "foo"}, keys)
+	assert.Equal(t, []int{3, 2, 1}, values)
+
+	// stopping early
+	keys = nil
+	om.ForEachReverse(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"baz", "bar"}, keys)
+}
+
+func TestIterator(t *testing.T) {
+	om := New[string, int]()
+	for range om.Iterator() {
+		t.Fatal("shouldn't iterate over an empty map")
+	}
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	var keys []string
+	var values []int
+	for key, value := range om.Iterator() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	assert.Equal(t, []string{"foo", "bar", "baz"}, keys)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	// breaking out early
+	keys = nil
+	for key := range om.Iterator() {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"foo", "bar"}, keys)
+}
+
+func TestSeq(t *testing.T) {
+	om := New[string, int]()
+	for range om.Seq() {
+		t.Fatal("shouldn't iterate over an empty map")
+	}
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	var pairs []*Pair[string, int]
+	for pair := range om.Seq() {
+		pairs = append(pairs, pair)
+	}
+	if assert.Len(t, pairs, 3) {
+		assert.Same(t, om.GetPair("foo"), pairs[0])
+		assert.Same(t, om.GetPair("bar"), pairs[1])
+		assert.Same(t, om.GetPair("baz"), pairs[2])
+	}
+
+	// breaking out early
+	pairs = nil
+	for pair := range om.Seq() {
+		pairs = append(pairs, pair)
+		break
+	}
+	assert.Len(t, pairs, 1)
+}
+
+func TestReverseIterator(t *testing.T) {
+	om := New[string, int]()
+	for range om.ReverseIterator() {
+		t.Fatal("shouldn't iterate over an empty map")
+	}
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	var keys []string
+	var values []int
+	for key, value := range om.ReverseIterator() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	assert.Equal(t, []string{"baz", "bar", "foo"}, keys)
+	assert.Equal(t, []int{3, 2, 1}, values)
+
+	// breaking out early
+	keys = nil
+	for key := range om.ReverseIterator() {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"baz", "bar"}, keys)
+}
+
+func TestKeysSeqAndValuesSeq(t *testing.T) {
+	om := New[string, int]()
+	assert.Empty(t, slices.Collect(om.KeysSeq()))
+	assert.Empty(t, slices.Collect(om.ValuesSeq()))
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.Equal(t, []string{"foo", "bar", "baz"}, slices.Collect(om.KeysSeq()))
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(om.ValuesSeq()))
+
+	// breaking out early
+	var keys []string
+	for key := range om.KeysSeq() {
+		keys = append(keys, key)
+		break
+	}
+	assert.Equal(t, []string{"foo"}, keys)
+
+	var values []int
+	for value := range om.ValuesSeq() {
+		values = append(values, value)
+		if len(values) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, values)
+}
+
+func TestFrom(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	var keys []int
+	for key, value := range om.From(7) {
+		assert.Equal(t, 2*key, value)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{7, 8, 9}, keys)
+
+	for range om.From(12) {
+		t.Fatal("shouldn't iterate from an absent key")
+	}
+
+	// breaking out early
+	keys = nil
+	for key := range om.From(3) {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{3, 4}, keys)
+}
+
+func TestBetween(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	collect := func(from, to int) []int {
+		var keys []int
+		for key, value := range om.Between(from, to) {
+			assert.Equal(t, 2*key, value)
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	assert.Equal(t, []int{2, 3, 4, 5}, collect(2, 5))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, collect(0, 9))
+	assert.Equal(t, []int{4}, collect(4, 4))
+	assert.Empty(t, collect(5, 2))
+	assert.Empty(t, collect(12, 2))
+	assert.Empty(t, collect(2, 12))
+
+	// follows the map's order rather than the keys'
+	om.MoveToBack(3)
+	assert.Equal(t, []int{8, 9, 3}, collect(8, 3))
+	assert.Empty(t, collect(3, 8))
+
+	// breaking out early
+	var keys []int
+	for key := range om.Between(0, 9) {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 1}, keys)
+}
+
+func TestChunks(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 7; i++ {
+		om.Set(i, 2*i)
+	}
+
+	collect := func(size int) [][]int {
+		var chunks [][]int
+		for chunk := range om.Chunks(size) {
+			var keys []int
+			for _, pair := range chunk {
+				assert.Equal(t, 2*pair.Key, pair.Value)
+				keys = append(keys, pair.Key)
+			}
+			chunks = append(chunks, keys)
+		}
+		return chunks
+	}
+
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}, collect(3))
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5, 6}}, collect(7))
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5, 6}}, collect(500))
+	assert.Len(t, collect(1), 7)
+
+	for range New[int, int]().Chunks(3) {
+		t.Error("an empty map shouldn't yield any chunk")
+	}
+
+	// breaking out early
+	var chunks int
+	for range om.Chunks(2) {
+		chunks++
+		break
+	}
+	assert.Equal(t, 1, chunks)
+
+	// kept chunks are unaffected by later changes to the map
+	kept := slices.Collect(om.Chunks(3))
+	om.DeleteAll([]int{0, 1, 2})
+	om.Set(12, 24)
+	assert.Equal(t, 0, kept[0][0].Key)
+	assert.Equal(t, 0, kept[0][0].Value)
+	assert.Equal(t, 2, kept[0][2].Key)
+	assert.Equal(t, [][]int{{3, 4, 5}, {6, 12}}, collect(3))
+
+	assert.Panics(t, func() { om.Chunks(0) })
+	assert.Panics(t, func() { om.Chunks(-1) })
+}
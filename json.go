@@ -0,0 +1,230 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+var (
+	_ json.Marshaler   = &OrderedMap[int,any]{}
+	_ json.Unmarshaler = &OrderedMap[int,any]{}
+)
+
+// MarshalJSON implements the json.Marshaler interface.
+// Pairs are emitted as a JSON object, from the oldest to the newest.
+// Keys follow the same rules as encoding/json uses for map keys: they must either
+// be strings, integers, or implement encoding.TextMarshaler. Values implementing
+// json.Marshaler, such as nested OrderedMaps, are marshaled with their own MarshalJSON.
+func (om *OrderedMap[K,V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := om.EncodeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONIndent is like MarshalJSON, but indents its output the way json.MarshalIndent does,
+// keeping the pairs in the map's order, nested OrderedMaps included.
+func (om *OrderedMap[K,V]) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	b, err := om.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeJSON writes the ordered map to w as a JSON object, the same way MarshalJSON does, but
+// one pair at a time, so that at most one encoded value needs to be held in memory at once.
+func (om *OrderedMap[K,V]) EncodeJSON(w io.Writer) error {
+	buf := []byte{'{'}
+
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if pair != om.Oldest() {
+			buf = append(buf, ',')
+		}
+
+		key, err := encodeKey(pair.Key)
+		if err != nil {
+			return err
+		}
+		// json.Marshal on a string can't fail
+		encodedKey, _ := json.Marshal(key)
+		buf = append(buf, encodedKey...)
+		buf = append(buf, ':')
+
+		// marshaling a pointer to the value, rather than a copy of it, means that values with
+		// pointer-receiver MarshalJSON methods, including non-pointer nested OrderedMaps,
+		// get to use them, as they would as addressable struct fields
+		encodedValue, err := json.Marshal(&pair.Value)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, encodedValue...)
+
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	buf = append(buf, '}')
+	_, err := w.Write(buf)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The input must be a JSON object; its pairs are set in the order in which their keys
+// appear in the document. Keys must either be strings, integers, or implement
+// encoding.TextUnmarshaler, integer keys that don't fit in K being errors. Values are decoded
+// into V the same way encoding/json would, except that numbers decoded into interface values
+// become json.Numbers rather than float64s, so as not to lose precision.
+func (om *OrderedMap[K,V]) UnmarshalJSON(data []byte) error {
+	om.initialize()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return om.decodeJSON(dec)
+}
+
+// FromJSON creates a new OrderedMap from the given JSON object, following the same rules as
+// UnmarshalJSON. Errors mention the offset in data at which decoding failed.
+func FromJSON[V any](data []byte) (*OrderedMap[string,V], error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	om := New[string,V]()
+	err := om.decodeJSON(dec)
+	if err == nil {
+		if _, trailingErr := dec.Token(); trailingErr != io.EOF {
+			err = errors.New("unexpected data after the JSON object")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("orderedmap: parsing JSON at offset %d: %w", dec.InputOffset(), err)
+	}
+	return om, nil
+}
+
+// DecodeJSON reads a JSON object from r, and returns an ordered map holding its pairs in the order
+// in which they appear in the document. The object is decoded incrementally, one pair at a time,
+// as it's read from r, following the same rules as UnmarshalJSON. Data that may follow the object
+// in r is not consumed, beyond what the decoder buffers.
+func DecodeJSON[V any](r io.Reader) (*OrderedMap[string,V], error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	om := New[string,V]()
+	if err := om.decodeJSON(dec); err != nil {
+		return nil, err
+	}
+	return om, nil
+}
+
+// decodeJSON reads a single JSON object from the decoder, one key at a time.
+func (om *OrderedMap[K,V]) decodeJSON(dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		// null is a no-op, as it is for encoding/json
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("orderedmap: expected a JSON object, got %v", token)
+	}
+
+	for dec.More() {
+		token, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		// object keys are always strings; the decoder errors out otherwise
+		key, err := decodeKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+
+	// consume the closing '}'
+	_, err = dec.Token()
+	return err
+}
+
+// encodeKey converts a key to its string representation, the same way encoding/json does for map keys.
+func encodeKey[K comparable](key K) (string, error) {
+	value := reflect.ValueOf(key)
+	if !value.IsValid() {
+		return "", fmt.Errorf("orderedmap: unsupported nil key")
+	}
+
+	if value.Kind() == reflect.String {
+		return value.String(), nil
+	}
+	if marshaler, ok := any(key).(encoding.TextMarshaler); ok {
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			return "", nil
+		}
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("orderedmap: unsupported key type %T", key)
+}
+
+// decodeKey parses a JSON object key into a K, the same way encoding/json does for map keys.
+func decodeKey[K comparable](raw string) (K, error) {
+	var key K
+
+	if unmarshaler, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := unmarshaler.UnmarshalText([]byte(raw))
+		return key, err
+	}
+
+	value := reflect.ValueOf(&key).Elem()
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// parsing with the key type's size means out of range keys are errors rather than truncated
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("orderedmap: invalid %T key %q: %w", key, raw, err)
+		}
+		value.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("orderedmap: invalid %T key %q: %w", key, raw, err)
+		}
+		value.SetUint(n)
+		return key, nil
+	}
+
+	return key, fmt.Errorf("orderedmap: unsupported key type %T", key)
+}
@@ -0,0 +1,427 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	t.Run("string keys", func(t *testing.T) {
+		om := New[string, any]()
+		om.Set("foo", "bar")
+		om.Set("an", 78)
+		om.Set("list", []int{1, 2, 3})
+		om.Set("x", nil)
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"foo":"bar","an":78,"list":[1,2,3],"x":null}`, string(b))
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		om := New[int, string]()
+		om.Set(12, "foo")
+		om.Set(-1, "bar")
+		om.Set(3, "baz")
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"12":"foo","-1":"bar","3":"baz"}`, string(b))
+	})
+
+	t.Run("TextMarshaler keys", func(t *testing.T) {
+		om := New[netip.Addr, int]()
+		om.Set(netip.MustParseAddr("127.0.0.1"), 1)
+		om.Set(netip.MustParseAddr("10.0.0.1"), 2)
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"127.0.0.1":1,"10.0.0.1":2}`, string(b))
+	})
+
+	t.Run("keys get escaped", func(t *testing.T) {
+		om := New[string, int]()
+		om.Set(`"quoted"`, 1)
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"\"quoted\"":1}`, string(b))
+	})
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		om := New[float64, string]()
+		om.Set(1.5, "foo")
+
+		_, err := json.Marshal(om)
+		assert.Error(t, err)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var s struct {
+			Map OrderedMap[string, int]
+		}
+		b, err := json.Marshal(&s)
+		require.NoError(t, err)
+		assert.Equal(t, `{"Map":{}}`, string(b))
+
+		om := New[string, OrderedMap[string, int]]()
+		om.Set("x", OrderedMap[string, int]{})
+		b, err = json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"x":{}}`, string(b))
+
+		decoded := New[string, *OrderedMap[string, int]]()
+		require.NoError(t, json.Unmarshal(b, decoded))
+		assertLenEqual(t, decoded.MustGet("x"), 0)
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		om := New[string, string]()
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(b))
+	})
+}
+
+func TestMarshalJSONNested(t *testing.T) {
+	t.Run("pointers", func(t *testing.T) {
+		innermost := New[string, int]()
+		innermost.Set("z", 26)
+		innermost.Set("a", 1)
+
+		inner := New[string, *OrderedMap[string, int]]()
+		inner.Set("second", innermost)
+		inner.Set("first", New[string, int]())
+
+		om := New[string, *OrderedMap[string, *OrderedMap[string, int]]]()
+		om.Set("outer", inner)
+		om.Set("nil", nil)
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"outer":{"second":{"z":26,"a":1},"first":{}},"nil":null}`, string(b))
+
+		decoded := New[string, *OrderedMap[string, *OrderedMap[string, int]]]()
+		require.NoError(t, json.Unmarshal(b, decoded))
+		assert.Equal(t, []string{"outer", "nil"}, decoded.Keys())
+		assert.Equal(t, []string{"second", "first"}, decoded.MustGet("outer").Keys())
+		assert.Equal(t, []string{"z", "a"}, decoded.MustGet("outer").MustGet("second").Keys())
+	})
+
+	t.Run("values with pointer-receiver marshalers", func(t *testing.T) {
+		assertNestedValuesMarshal(t, json.Marshal, `{"x":"custom:foo"}`)
+	})
+
+	t.Run("inside other values", func(t *testing.T) {
+		inner := New[string, int]()
+		inner.Set("z", 26)
+		inner.Set("a", 1)
+
+		om := New[string, []*OrderedMap[string, int]]()
+		om.Set("list", []*OrderedMap[string, int]{inner, inner})
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"list":[{"z":26,"a":1},{"z":26,"a":1}]}`, string(b))
+	})
+}
+
+func TestMarshalJSONIndent(t *testing.T) {
+	inner := New[string, int]()
+	inner.Set("z", 26)
+	inner.Set("a", 1)
+
+	om := New[string, any]()
+	om.Set("foo", "bar")
+	om.Set("nested", inner)
+	om.Set("list", []int{1, 2})
+	om.Set("empty", New[string, int]())
+
+	b, err := om.MarshalJSONIndent("", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "foo": "bar",
+  "nested": {
+    "z": 26,
+    "a": 1
+  },
+  "list": [
+    1,
+    2
+  ],
+  "empty": {}
+}`, string(b))
+
+	b, err = inner.MarshalJSONIndent("> ", "\t")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n> \t\"z\": 26,\n> \t\"a\": 1\n> }", string(b))
+
+	invalid := New[string, any]()
+	invalid.Set("chan", make(chan int))
+	_, err = invalid.MarshalJSONIndent("", "  ")
+	assert.Error(t, err)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	om := New[string, any]()
+	om.Set("foo", "bar")
+	om.Set("an", 78)
+	om.Set("nested", map[string]int{"x": 1})
+
+	var buf bytes.Buffer
+	require.NoError(t, om.EncodeJSON(&buf))
+	assert.Equal(t, `{"foo":"bar","an":78,"nested":{"x":1}}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, New[string, int]().EncodeJSON(&buf))
+	assert.Equal(t, `{}`, buf.String())
+
+	// errors are propagated
+	assert.Error(t, om.EncodeJSON(failingWriter{}))
+	invalid := New[string, any]()
+	invalid.Set("chan", make(chan int))
+	buf.Reset()
+	assert.Error(t, invalid.EncodeJSON(&buf))
+}
+
+// pointerMarshaler only marshals through pointer receivers, the way OrderedMap itself does;
+// each format's tests give it the relevant method.
+type pointerMarshaler string
+
+func (v *pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal("custom:" + string(*v))
+}
+
+// assertNestedValuesMarshal checks that marshal encodes the values of an ordered map with their own
+// pointer-receiver methods, be they held as values or pointers, into expected either way.
+func assertNestedValuesMarshal(t *testing.T, marshal func(any) ([]byte, error), expected string) {
+	value := pointerMarshaler("foo")
+	values := New[string, pointerMarshaler]()
+	values.Set("x", value)
+	pointers := New[string, *pointerMarshaler]()
+	pointers.Set("x", &value)
+
+	for _, om := range []any{values, pointers} {
+		b, err := marshal(om)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, string(b))
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Run("preserves the document's key order", func(t *testing.T) {
+		data := `{"foo":"bar","an":78,"nested":{"z":1,"a":2},"list":[1,2],"x":null}`
+
+		om := New[string, any]()
+		require.NoError(t, json.Unmarshal([]byte(data), om))
+
+		var keys []string
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			keys = append(keys, pair.Key)
+		}
+		assert.Equal(t, []string{"foo", "an", "nested", "list", "x"}, keys)
+
+		value, _ := om.Get("nested")
+		assert.Equal(t, map[string]any{"z": json.Number("1"), "a": json.Number("2")}, value)
+	})
+
+	t.Run("numbers keep their precision", func(t *testing.T) {
+		om := New[string, any]()
+		require.NoError(t, json.Unmarshal([]byte(`{"id":9007199254740993,"ratio":0.5,"typed":[1]}`), om))
+
+		assert.Equal(t, json.Number("9007199254740993"), om.MustGet("id"))
+		assert.Equal(t, json.Number("0.5"), om.MustGet("ratio"))
+		assert.Equal(t, []any{json.Number("1")}, om.MustGet("typed"))
+
+		// non-interface values are unaffected
+		typed := New[string, int64]()
+		require.NoError(t, json.Unmarshal([]byte(`{"id":9007199254740993}`), typed))
+		assert.Equal(t, int64(9007199254740993), typed.MustGet("id"))
+
+		// and it round trips
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":9007199254740993,"ratio":0.5,"typed":[1]}`, string(b))
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		type point struct {
+			X, Y int
+		}
+
+		om := New[string, point]()
+		require.NoError(t, json.Unmarshal([]byte(`{"b":{"X":1,"Y":2},"a":{"X":3}}`), om))
+
+		assert.Equal(t, 2, om.Len())
+		assert.Equal(t, "b", om.Oldest().Key)
+		assert.Equal(t, point{1, 2}, om.Oldest().Value)
+		assert.Equal(t, "a", om.Newest().Key)
+		assert.Equal(t, point{3, 0}, om.Newest().Value)
+	})
+
+	t.Run("TextUnmarshaler keys", func(t *testing.T) {
+		om := New[netip.Addr, int]()
+		require.NoError(t, json.Unmarshal([]byte(`{"127.0.0.1":1,"10.0.0.1":2}`), om))
+
+		assert.Equal(t, netip.MustParseAddr("127.0.0.1"), om.Oldest().Key)
+		assert.Equal(t, netip.MustParseAddr("10.0.0.1"), om.Newest().Key)
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		om := New[int64, string]()
+		om.Set(12, "foo")
+		om.Set(-1, "bar")
+		om.Set(math.MaxInt64, "max")
+		om.Set(math.MinInt64, "min")
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `{"12":"foo","-1":"bar","9223372036854775807":"max","-9223372036854775808":"min"}`, string(b))
+
+		decoded := New[int64, string]()
+		require.NoError(t, json.Unmarshal(b, decoded))
+		assertOrderedPairsEqual[int64, string](t, decoded,
+			[]int64{12, -1, math.MaxInt64, math.MinInt64}, []string{"foo", "bar", "max", "min"})
+
+		unsigned := New[uint16, string]()
+		require.NoError(t, json.Unmarshal([]byte(`{"65535":"max","0":"zero"}`), unsigned))
+		assertOrderedPairsEqual[uint16, string](t, unsigned, []uint16{65535, 0}, []string{"max", "zero"})
+	})
+
+	t.Run("invalid integer keys", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"9223372036854775808":"overflow"}`), New[int64, string]())
+		assertErrorContains(t, err, `orderedmap: invalid int64 key "9223372036854775808"`)
+		assert.True(t, errors.Is(err, strconv.ErrRange))
+
+		err = json.Unmarshal([]byte(`{"128":"overflow"}`), New[int8, string]())
+		assert.True(t, errors.Is(err, strconv.ErrRange))
+
+		err = json.Unmarshal([]byte(`{"-1":"negative"}`), New[uint, string]())
+		assertErrorContains(t, err, `orderedmap: invalid uint key "-1"`)
+
+		err = json.Unmarshal([]byte(`{"1.5":"not an integer"}`), New[int, string]())
+		assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	})
+
+	t.Run("into a zero value", func(t *testing.T) {
+		var s struct {
+			Map *OrderedMap[string, int]
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"Map":{"b":2,"a":1}}`), &s))
+
+		if assert.NotNil(t, s.Map) {
+			assert.Equal(t, "b", s.Map.Oldest().Key)
+			assert.Equal(t, "a", s.Map.Newest().Key)
+			assertLenEqual(t, s.Map, 2)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		om := New[string, int]()
+		om.Set("z", 26)
+		om.Set("a", 1)
+		om.Set("m", 13)
+
+		b, err := json.Marshal(om)
+		require.NoError(t, err)
+
+		decoded := New[string, int]()
+		require.NoError(t, json.Unmarshal(b, decoded))
+		assertOrderedPairsEqual[string, int](t, decoded, []string{"z", "a", "m"}, []int{26, 1, 13})
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		om := New[string, int]()
+		err := om.UnmarshalJSON([]byte(`[1,2]`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "expected a JSON object")
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		om := New[string, int]()
+		assert.Error(t, json.Unmarshal([]byte(`{"a":"not an int"}`), om))
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("preserves the document's key order", func(t *testing.T) {
+		r := strings.NewReader(`{"foo":"bar","an":78,"nested":{"z":1}}`)
+
+		om, err := DecodeJSON[any](r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", "an", "nested"}, om.Keys())
+		assert.Equal(t, []any{"bar", json.Number("78"), map[string]any{"z": json.Number("1")}}, om.Values())
+	})
+
+	t.Run("large documents", func(t *testing.T) {
+		n := 10000
+		var builder strings.Builder
+		builder.WriteByte('{')
+		for i := n - 1; i >= 0; i-- {
+			fmt.Fprintf(&builder, `"key%d":%d`, i, i)
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+		}
+		builder.WriteByte('}')
+
+		om, err := DecodeJSON[int](iotest.OneByteReader(strings.NewReader(builder.String())))
+		require.NoError(t, err)
+		assertLenEqual(t, om, n)
+		assert.Equal(t, "key9999", om.Oldest().Key)
+		assert.Equal(t, 0, om.Newest().Value)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := DecodeJSON[int](strings.NewReader(`[1]`))
+		assertErrorContains(t, err, "expected a JSON object")
+
+		_, err = DecodeJSON[int](strings.NewReader(`{"a":1,`))
+		assert.Error(t, err)
+
+		_, err = DecodeJSON[int](strings.NewReader(``))
+		assert.Error(t, err)
+	})
+}
+
+func TestFromJSON(t *testing.T) {
+	om, err := FromJSON[int]([]byte(`{"z":26,"a":1,"m":13}`))
+	require.NoError(t, err)
+	assertOrderedPairsEqual[string, int](t, om, []string{"z", "a", "m"}, []int{26, 1, 13})
+
+	om, err = FromJSON[int]([]byte(` {} `))
+	require.NoError(t, err)
+	assertLenEqual(t, om, 0)
+
+	_, err = FromJSON[int]([]byte(`{"a":1,"b":"two"}`))
+	assertErrorContains(t, err, "orderedmap: parsing JSON at offset 16: ")
+
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr))
+
+	_, err = FromJSON[int]([]byte(`{"a":1} {"b":2}`))
+	assertErrorContains(t, err, "unexpected data after the JSON object")
+
+	_, err = FromJSON[int]([]byte(`[1]`))
+	assertErrorContains(t, err, "expected a JSON object")
+}
@@ -0,0 +1,76 @@
+package orderedmap
+
+// LRU is an ordered map bounded in size: once it's full, setting a new key evicts the least
+// recently used pair, i.e. the oldest one. Pairs are promoted to the newest position whenever
+// they get accessed through Get or Set.
+type LRU[K comparable, V any] struct {
+	// OnEvict, if not nil, is called with every pair that gets evicted to make room for a new one.
+	OnEvict func(key K, value V)
+
+	om         *OrderedMap[K,V]
+	maxEntries int
+}
+
+// NewLRU creates a new LRU holding at most maxEntries pairs; 0 means no limit.
+func NewLRU[K comparable, V any](maxEntries int) *LRU[K,V] {
+	return &LRU[K,V]{
+		om:         New[K,V](),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get looks for the given key, and returns the value associated with it, promoting it to
+// the newest position. The boolean it returns says whether the key is present in the map.
+func (l *LRU[K,V]) Get(key K) (V, bool) {
+	if pair := l.om.GetPair(key); pair != nil {
+		l.om.list.MoveToBack(pair.element)
+		return pair.Value, true
+	}
+	var empty V
+	return empty, false
+}
+
+// Peek looks for the given key, and returns the value associated with it, without promoting it.
+// The boolean it returns says whether the key is present in the map.
+func (l *LRU[K,V]) Peek(key K) (V, bool) {
+	return l.om.Peek(key)
+}
+
+// Set sets the key-value pair as the newest one, and returns what `Get` would have returned
+// on that key prior to the call to `Set`. If the key is new and the map is full, the oldest
+// pair gets evicted first.
+func (l *LRU[K,V]) Set(key K, value V) (V, bool) {
+	if pair := l.om.GetPair(key); pair != nil {
+		oldValue := pair.Value
+		pair.Value = value
+		l.om.list.MoveToBack(pair.element)
+		return oldValue, true
+	}
+
+	if l.maxEntries > 0 && l.om.Len() >= l.maxEntries {
+		evictedKey, evictedValue, _ := l.om.PopOldest()
+		if l.OnEvict != nil {
+			l.OnEvict(evictedKey, evictedValue)
+		}
+	}
+	l.om.pushBack(key, value)
+
+	var empty V
+	return empty, false
+}
+
+// Delete removes the key-value pair, and returns what `Get` would have returned
+// on that key prior to the call to `Delete`. OnEvict is not called.
+func (l *LRU[K,V]) Delete(key K) (V, bool) {
+	return l.om.Delete(key)
+}
+
+// Len returns the number of pairs in the map.
+func (l *LRU[K,V]) Len() int {
+	return l.om.Len()
+}
+
+// Keys returns the map's keys, from the least to the most recently used.
+func (l *LRU[K,V]) Keys() []K {
+	return l.om.Keys()
+}
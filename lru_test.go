@@ -0,0 +1,76 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	var evicted []string
+	l := NewLRU[string, int](3)
+	l.OnEvict = func(key string, value int) {
+		evicted = append(evicted, key)
+		assert.Equal(t, len(key), value)
+	}
+
+	l.Set("a", 1)
+	l.Set("bb", 2)
+	l.Set("ccc", 3)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, l.Keys())
+	assert.Empty(t, evicted)
+
+	// getting promotes
+	value, present := l.Get("a")
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+	assert.Equal(t, []string{"bb", "ccc", "a"}, l.Keys())
+
+	// evicts the least recently used
+	l.Set("dddd", 4)
+	assert.Equal(t, []string{"ccc", "a", "dddd"}, l.Keys())
+	assert.Equal(t, []string{"bb"}, evicted)
+	_, present = l.Get("bb")
+	assert.False(t, present)
+
+	// updating promotes, and doesn't evict
+	oldValue, present := l.Set("ccc", 3)
+	assert.Equal(t, 3, oldValue)
+	assert.True(t, present)
+	assert.Equal(t, []string{"a", "dddd", "ccc"}, l.Keys())
+	assert.Equal(t, []string{"bb"}, evicted)
+
+	// deleting makes room, and doesn't count as an eviction
+	value, present = l.Delete("a")
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+	l.Set("ee", 2)
+	assert.Equal(t, 3, l.Len())
+	assert.Equal(t, []string{"bb"}, evicted)
+}
+
+func TestLRUPeek(t *testing.T) {
+	l := NewLRU[string, int](2)
+	l.Set("foo", 1)
+	l.Set("bar", 2)
+
+	value, present := l.Peek("foo")
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+	assert.Equal(t, []string{"foo", "bar"}, l.Keys())
+
+	// foo didn't get promoted, so it's the one evicted
+	l.Set("baz", 3)
+	assert.Equal(t, []string{"bar", "baz"}, l.Keys())
+
+	_, present = l.Peek("foo")
+	assert.False(t, present)
+}
+
+func TestUnboundedLRU(t *testing.T) {
+	l := NewLRU[int, int](0)
+	for i := 0; i < 100; i++ {
+		l.Set(i, i)
+	}
+	assert.Equal(t, 100, l.Len())
+}
@@ -0,0 +1,63 @@
+//go:build msgpack
+
+package orderedmap
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	_ msgpack.Marshaler   = &OrderedMap[int,any]{}
+	_ msgpack.Unmarshaler = &OrderedMap[int,any]{}
+)
+
+// MarshalMsgpack implements the msgpack.Marshaler interface from github.com/vmihailenco/msgpack.
+// Pairs are encoded as a MessagePack map, from the oldest to the newest. Requires the msgpack build tag.
+func (om *OrderedMap[K,V]) MarshalMsgpack() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+
+	if err := enc.EncodeMapLen(om.Len()); err != nil {
+		return nil, err
+	}
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if err := enc.Encode(pair.Key); err != nil {
+			return nil, err
+		}
+		// see EncodeJSON for why a pointer to the value is encoded
+		if err := enc.Encode(&pair.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMsgpack implements the msgpack.Unmarshaler interface from github.com/vmihailenco/msgpack.
+// The input must be a MessagePack map; its pairs are set in the order in which they appear in the stream.
+func (om *OrderedMap[K,V]) UnmarshalMsgpack(data []byte) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+
+	length, err := dec.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+
+	om.initialize()
+	// a nil map has a length of -1, and is a no-op
+	for i := 0; i < length; i++ {
+		var key K
+		if err := dec.Decode(&key); err != nil {
+			return err
+		}
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build msgpack
+
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgpack(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		om := New[string, int]()
+		om.Set("z", 26)
+		om.Set("a", 1)
+		om.Set("m", 13)
+
+		b, err := msgpack.Marshal(om)
+		require.NoError(t, err)
+
+		decoded := New[string, int]()
+		require.NoError(t, msgpack.Unmarshal(b, decoded))
+		assertOrderedPairsEqual[string, int](t, decoded, []string{"z", "a", "m"}, []int{26, 1, 13})
+	})
+
+	t.Run("encodes a regular map", func(t *testing.T) {
+		om := New[string, any]()
+		om.Set("foo", "bar")
+		om.Set("an", int64(78))
+
+		b, err := msgpack.Marshal(om)
+		require.NoError(t, err)
+
+		var m map[string]any
+		require.NoError(t, msgpack.Unmarshal(b, &m))
+		assert.Equal(t, map[string]any{"foo": "bar", "an": int64(78)}, m)
+	})
+
+	t.Run("values with pointer-receiver marshalers", func(t *testing.T) {
+		expected, err := msgpack.Marshal(map[string]string{"x": "custom:foo"})
+		require.NoError(t, err)
+		assertNestedValuesMarshal(t, msgpack.Marshal, string(expected))
+	})
+
+	t.Run("decodes in stream order, into a zero value", func(t *testing.T) {
+		type wrapper struct {
+			Map *OrderedMap[int, string]
+		}
+
+		om := New[int, string]()
+		om.Set(12, "foo")
+		om.Set(1, "bar")
+		om.Set(7, "baz")
+
+		b, err := msgpack.Marshal(wrapper{om})
+		require.NoError(t, err)
+
+		var decoded wrapper
+		require.NoError(t, msgpack.Unmarshal(b, &decoded))
+		if assert.NotNil(t, decoded.Map) {
+			assertOrderedPairsEqual[int, string](t, decoded.Map, []int{12, 1, 7}, []string{"foo", "bar", "baz"})
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		type wrapper struct {
+			Map OrderedMap[string, int]
+		}
+
+		b, err := msgpack.Marshal(&wrapper{})
+		require.NoError(t, err)
+
+		var decoded wrapper
+		require.NoError(t, msgpack.Unmarshal(b, &decoded))
+		assertLenEqual(t, &decoded.Map, 0)
+	})
+
+	t.Run("not a map", func(t *testing.T) {
+		b, err := msgpack.Marshal([]int{1, 2})
+		require.NoError(t, err)
+
+		assert.Error(t, msgpack.Unmarshal(b, New[string, int]()))
+	})
+}
+
+func (v *pointerMarshaler) MarshalMsgpack() ([]byte, error) {
+	return msgpack.Marshal("custom:" + string(*v))
+}
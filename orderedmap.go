@@ -8,9 +8,17 @@
 package orderedmap
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/DominicTobias/go-ordered-map/list"
 )
 
+// ErrKeyNotFound is returned by operations that require a key to be present in the map.
+var ErrKeyNotFound = errors.New("orderedmap: key not found")
+
 type Pair[K comparable, V any] struct {
 	Key   K
 	Value V
@@ -18,11 +26,29 @@ type Pair[K comparable, V any] struct {
 	element *list.Element[*Pair[K,V]]
 }
 
+// OrderedMap is a map that remembers the order in which keys were inserted.
+// It must not be copied after first use: copies would share its pairs, but not its size.
 type OrderedMap[K comparable, V any] struct {
+	noCopy noCopy
+
 	pairs map[K]*Pair[K,V]
 	list  *list.List[*Pair[K,V]]
+	// size is the number of pairs, maintained by insert and removePair so that Len doesn't
+	// need to look at the map
+	size int
+	// capacity is how many pairs the index was last sized for, by NewWithCapacity, Grow or Shrink;
+	// Go doesn't expose a map's capacity, so this is what Grow goes by
+	capacity int
+
+	onDelete func(key K, value V)
 }
 
+// noCopy makes go vet's copylocks check flag copies of the structs embedding it.
+type noCopy struct{}
+
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
+
 // New creates a new OrderedMap.
 func New[K comparable, V any]() *OrderedMap[K,V] {
 	return &OrderedMap[K,V]{
@@ -31,6 +57,42 @@ func New[K comparable, V any]() *OrderedMap[K,V] {
 	}
 }
 
+// NewWithCapacity creates a new OrderedMap, with enough space allocated up front
+// to hold the given number of pairs without having to grow.
+func NewWithCapacity[K comparable, V any](capacity int) *OrderedMap[K,V] {
+	return &OrderedMap[K,V]{
+		pairs:    make(map[K]*Pair[K,V], capacity),
+		list:     list.New[*Pair[K,V]](),
+		capacity: capacity,
+	}
+}
+
+// FromMap creates a new OrderedMap holding the pairs from m, ordered by the given keys.
+// Keys from order that are not in m are skipped, and only the first occurrence of a key
+// duplicated in order is taken into account. Keys from m that are not in order are appended
+// at the end, in Go's unspecified map iteration order.
+func FromMap[K comparable, V any](m map[K]V, order []K) *OrderedMap[K,V] {
+	om := NewWithCapacity[K,V](len(m))
+	for _, key := range order {
+		if value, present := m[key]; present {
+			om.SetIfAbsent(key, value)
+		}
+	}
+	for key, value := range m {
+		om.SetIfAbsent(key, value)
+	}
+	return om
+}
+
+// initialize makes a zero-value OrderedMap ready for use, e.g. when one is
+// allocated by a decoder rather than through New.
+func (om *OrderedMap[K,V]) initialize() {
+	if om.pairs == nil {
+		om.pairs = make(map[K]*Pair[K,V])
+		om.list = list.New[*Pair[K,V]]()
+	}
+}
+
 // Get looks for the given key, and returns the value associated with it,
 // or nil if not found. The boolean it returns says whether the key is present in the map.
 func (om *OrderedMap[K,V]) Get(key K) (V, bool) {
@@ -41,6 +103,21 @@ func (om *OrderedMap[K,V]) Get(key K) (V, bool) {
 	return empty, false
 }
 
+// MustGet returns the value associated with the given key, and panics with an error
+// wrapping ErrKeyNotFound if it's not present.
+func (om *OrderedMap[K,V]) MustGet(key K) V {
+	if pair, present := om.pairs[key]; present {
+		return pair.Value
+	}
+	panic(fmt.Errorf("%w: %v", ErrKeyNotFound, key))
+}
+
+// Peek is the same as Get, and is guaranteed never to affect the pairs' order.
+// It mirrors LRU.Peek, which unlike LRU.Get doesn't promote the pair it looks up.
+func (om *OrderedMap[K,V]) Peek(key K) (V, bool) {
+	return om.Get(key)
+}
+
 // GetPair looks for the given key, and returns the pair associated with it,
 // or nil if not found. The Pair struct can then be used to iterate over the ordered map
 // from that point, either forward or backward.
@@ -48,6 +125,27 @@ func (om *OrderedMap[K,V]) GetPair(key K) *Pair[K,V] {
 	return om.pairs[key]
 }
 
+// GetPairOk is like GetPair, but also returns whether the key is present, the same way Get does.
+func (om *OrderedMap[K,V]) GetPairOk(key K) (*Pair[K,V], bool) {
+	pair, present := om.pairs[key]
+	return pair, present
+}
+
+// GetOrDefault returns the value associated with the given key,
+// or the given default value if the key is not present. It never modifies the map.
+func (om *OrderedMap[K,V]) GetOrDefault(key K, def V) V {
+	if pair, present := om.pairs[key]; present {
+		return pair.Value
+	}
+	return def
+}
+
+// Has returns whether the given key is present in the map.
+func (om *OrderedMap[K,V]) Has(key K) bool {
+	_, present := om.pairs[key]
+	return present
+}
+
 // Set sets the key-value pair, and returns what `Get` would have returned
 // on that key prior to the call to `Set`.
 func (om *OrderedMap[K,V]) Set(key K, value V) (V, bool) {
@@ -57,24 +155,201 @@ func (om *OrderedMap[K,V]) Set(key K, value V) (V, bool) {
 		return oldValue, true
 	}
 
+	om.pushBack(key, value)
+
+	var empty V
+	return empty, false
+}
+
+// ReplaceValue updates the value associated with the given key, without moving it, and returns
+// the previous value. Unlike `Set`, it never inserts a new pair: if the key is not present,
+// it returns ErrKeyNotFound and leaves the map untouched.
+func (om *OrderedMap[K,V]) ReplaceValue(key K, value V) (V, error) {
+	pair, present := om.pairs[key]
+	if !present {
+		var empty V
+		return empty, ErrKeyNotFound
+	}
+
+	oldValue := pair.Value
+	pair.Value = value
+	return oldValue, nil
+}
+
+// CompareAndSwap sets the value associated with the given key to newValue if its current value is
+// equal to oldValue according to eq, and returns whether it did so. The pair keeps its position.
+func (om *OrderedMap[K,V]) CompareAndSwap(key K, oldValue, newValue V, eq func(V, V) bool) bool {
+	if pair, present := om.pairs[key]; present && eq(pair.Value, oldValue) {
+		pair.Value = newValue
+		return true
+	}
+	return false
+}
+
+// SetChanged sets the key-value pair, as `Set` would, and returns whether that changed anything,
+// i.e. whether the key was new or its previous value differed from the new one according to eq.
+func (om *OrderedMap[K,V]) SetChanged(key K, value V, eq func(V, V) bool) bool {
+	oldValue, present := om.Set(key, value)
+	return !present || !eq(oldValue, value)
+}
+
+// SetAll sets every given pair, in order, as `Set` would: new keys get appended in the slice's order,
+// and for keys appearing several times, the last value wins while the first occurrence sets the position.
+func (om *OrderedMap[K,V]) SetAll(pairs []Pair[K,V]) {
+	for _, pair := range pairs {
+		om.Set(pair.Key, pair.Value)
+	}
+}
+
+// SetFront sets the key-value pair, and returns what `Get` would have returned on that key prior
+// to the call to `SetFront`. Unlike `Set`, new keys are inserted as the oldest pair rather than the
+// newest; existing keys get their value updated in place, as with `Set`.
+func (om *OrderedMap[K,V]) SetFront(key K, value V) (V, bool) {
+	if pair, present := om.pairs[key]; present {
+		oldValue := pair.Value
+		pair.Value = value
+		return oldValue, true
+	}
+
+	om.insert(key, value, om.list.PushFront)
+
+	var empty V
+	return empty, false
+}
+
+// SetAndMove sets the key-value pair as the newest one, and returns what `Get` would have returned
+// on that key prior to the call to `SetAndMove`. Unlike `Set`, it moves existing keys to the back
+// of the ordered map, so that pairs are ordered by when they were last set rather than first inserted.
+func (om *OrderedMap[K,V]) SetAndMove(key K, value V) (V, bool) {
+	if pair, present := om.pairs[key]; present {
+		oldValue := pair.Value
+		pair.Value = value
+		om.list.MoveToBack(pair.element)
+		return oldValue, true
+	}
+
+	om.pushBack(key, value)
+
+	var empty V
+	return empty, false
+}
+
+// GetOrSet returns the value associated with the given key and true if it is present;
+// otherwise it sets the key-value pair as the newest one, and returns the given value and false.
+func (om *OrderedMap[K,V]) GetOrSet(key K, value V) (V, bool) {
+	if pair, present := om.pairs[key]; present {
+		return pair.Value, true
+	}
+
+	om.pushBack(key, value)
+	return value, false
+}
+
+// GetOrCompute returns the value associated with the given key if it is present;
+// otherwise it calls compute, sets its result as the newest pair, and returns it.
+// compute is only called if the key is absent.
+func (om *OrderedMap[K,V]) GetOrCompute(key K, compute func() V) V {
+	if pair, present := om.pairs[key]; present {
+		return pair.Value
+	}
+
+	return om.pushBack(key, compute()).Value
+}
+
+// SetIfAbsent sets the key-value pair as the newest one if the key is not already present,
+// and returns true; otherwise, it leaves the map untouched and returns false.
+func (om *OrderedMap[K,V]) SetIfAbsent(key K, value V) bool {
+	if _, present := om.pairs[key]; present {
+		return false
+	}
+
+	om.pushBack(key, value)
+	return true
+}
+
+// InsertAt sets the key-value pair, and returns what `Get` would have returned on that key
+// prior to the call to `InsertAt`. If the key is new, its pair is inserted just before the pair
+// currently at the given index (with the same semantics as At for negative indices), or as the
+// newest pair if the index is past the end of the map; otherwise its value is updated in place.
+// Like At, it's linear in the distance from the index to the closest end of the map.
+func (om *OrderedMap[K,V]) InsertAt(index int, key K, value V) (V, bool) {
+	if pair, present := om.pairs[key]; present {
+		oldValue := pair.Value
+		pair.Value = value
+		return oldValue, true
+	}
+
+	switch mark := om.At(index); {
+	case mark != nil:
+		om.insert(key, value, func(pair *Pair[K,V]) *list.Element[*Pair[K,V]] {
+			return om.list.InsertBefore(pair, mark.element)
+		})
+	case index < 0:
+		om.insert(key, value, om.list.PushFront)
+	default:
+		om.pushBack(key, value)
+	}
+
+	var empty V
+	return empty, false
+}
+
+// InsertBefore sets the key-value pair, and returns what `Get` would have returned on that key
+// prior to the call to `InsertBefore`. If the key is new, its pair is inserted just before the pair
+// with the mark key, or as the newest pair if mark is not present; otherwise its value is updated
+// in place. Use MoveBefore to move existing keys.
+func (om *OrderedMap[K,V]) InsertBefore(mark, key K, value V) (V, bool) {
+	return om.insertNextTo(mark, key, value, om.list.InsertBefore)
+}
+
+// InsertAfter is like InsertBefore, but inserts new keys just after the pair with the mark key.
+func (om *OrderedMap[K,V]) InsertAfter(mark, key K, value V) (V, bool) {
+	return om.insertNextTo(mark, key, value, om.list.InsertAfter)
+}
+
+// insertNextTo implements InsertBefore and InsertAfter, with the given list method.
+func (om *OrderedMap[K,V]) insertNextTo(mark, key K, value V, link func(*Pair[K,V], *list.Element[*Pair[K,V]]) *list.Element[*Pair[K,V]]) (V, bool) {
+	if pair, present := om.pairs[key]; present {
+		oldValue := pair.Value
+		pair.Value = value
+		return oldValue, true
+	}
+
+	if markPair, present := om.pairs[mark]; present {
+		om.insert(key, value, func(pair *Pair[K,V]) *list.Element[*Pair[K,V]] {
+			return link(pair, markPair.element)
+		})
+	} else {
+		om.pushBack(key, value)
+	}
+
+	var empty V
+	return empty, false
+}
+
+// pushBack inserts a new pair, whose key must not already be present in the map, as the newest one.
+func (om *OrderedMap[K,V]) pushBack(key K, value V) *Pair[K,V] {
+	return om.insert(key, value, om.list.PushBack)
+}
+
+// insert creates a new pair, whose key must not already be present in the map,
+// and links it into the list using the given function.
+func (om *OrderedMap[K,V]) insert(key K, value V, link func(*Pair[K,V]) *list.Element[*Pair[K,V]]) *Pair[K,V] {
 	pair := &Pair[K,V]{
 		Key:   key,
 		Value: value,
 	}
-	// cannot use pair (variable of type *Pair[K, V]) as type Pair[K, V] in argument to om.list.PushBack
-	pair.element = om.list.PushBack(pair)
+	pair.element = link(pair)
 	om.pairs[key] = pair
-
-	var empty V
-	return empty, false
+	om.size++
+	return pair
 }
 
 // Delete removes the key-value pair, and returns what `Get` would have returned
 // on that key prior to the call to `Delete`.
 func (om *OrderedMap[K,V]) Delete(key K) (V, bool) {
 	if pair, present := om.pairs[key]; present {
-		om.list.Remove(pair.element)
-		delete(om.pairs, key)
+		om.removePair(pair)
 		return pair.Value, true
 	}
 
@@ -82,15 +357,600 @@ func (om *OrderedMap[K,V]) Delete(key K) (V, bool) {
 	return empty, false
 }
 
+// CompareAndDelete removes the pair with the given key if its current value is equal to old
+// according to eq, and returns whether it did so.
+func (om *OrderedMap[K,V]) CompareAndDelete(key K, old V, eq func(V, V) bool) bool {
+	if pair, present := om.pairs[key]; present && eq(pair.Value, old) {
+		om.removePair(pair)
+		return true
+	}
+	return false
+}
+
+// DeleteFunc removes all the pairs for which pred returns true, and returns how many were removed.
+func (om *OrderedMap[K,V]) DeleteFunc(pred func(key K, value V) bool) int {
+	removed := 0
+	for pair := om.Oldest(); pair != nil; {
+		// grab the next pair before removing this one, as removal unlinks it
+		next := pair.Next()
+		if pred(pair.Key, pair.Value) {
+			om.removePair(pair)
+			removed++
+		}
+		pair = next
+	}
+	return removed
+}
+
+// DeleteAll removes the pairs with the given keys, and returns how many were actually removed.
+// Keys that are not present are ignored.
+func (om *OrderedMap[K,V]) DeleteAll(keys []K) int {
+	removed := 0
+	for _, key := range keys {
+		if pair, present := om.pairs[key]; present {
+			om.removePair(pair)
+			removed++
+		}
+	}
+	return removed
+}
+
+// PopOldest removes the oldest pair from the ordered map, and returns its key and value.
+// The boolean it returns is false if the map was empty.
+func (om *OrderedMap[K,V]) PopOldest() (K, V, bool) {
+	if pair := om.Oldest(); pair != nil {
+		om.removePair(pair)
+		return pair.Key, pair.Value, true
+	}
+
+	var emptyKey K
+	var emptyValue V
+	return emptyKey, emptyValue, false
+}
+
+// PopNewest removes the newest pair from the ordered map, and returns its key and value.
+// The boolean it returns is false if the map was empty.
+func (om *OrderedMap[K,V]) PopNewest() (K, V, bool) {
+	if pair := om.Newest(); pair != nil {
+		om.removePair(pair)
+		return pair.Key, pair.Value, true
+	}
+
+	var emptyKey K
+	var emptyValue V
+	return emptyKey, emptyValue, false
+}
+
+// PopAt removes the pair with the given key, and returns its value along with the zero-based
+// position it had in the ordered map, such that calling `InsertAt` with that index re-inserts it
+// where it was. The boolean it returns is false if the key was not present.
+// Like Index, it's linear in the key's position.
+func (om *OrderedMap[K,V]) PopAt(key K) (value V, index int, ok bool) {
+	pair, present := om.pairs[key]
+	if !present {
+		return value, -1, false
+	}
+
+	index = om.Index(key)
+	om.removePair(pair)
+	return pair.Value, index, true
+}
+
+// DeleteOldest removes the oldest pair from the ordered map, and returns false if the map was empty.
+func (om *OrderedMap[K,V]) DeleteOldest() bool {
+	_, _, ok := om.PopOldest()
+	return ok
+}
+
+// DeleteNewest removes the newest pair from the ordered map, and returns false if the map was empty.
+func (om *OrderedMap[K,V]) DeleteNewest() bool {
+	_, _, ok := om.PopNewest()
+	return ok
+}
+
+// removePair removes the given pair, which must be present in the map, from both the list and the index,
+// and calls the onDelete callback if there's one.
+func (om *OrderedMap[K,V]) removePair(pair *Pair[K,V]) {
+	om.list.Remove(pair.element)
+	delete(om.pairs, pair.Key)
+	om.size--
+
+	if om.onDelete != nil {
+		om.onDelete(pair.Key, pair.Value)
+	}
+}
+
+// Clear removes all pairs from the ordered map, which can then be reused.
+func (om *OrderedMap[K,V]) Clear() {
+	if om.onDelete != nil {
+		// go through removePair so that the callback gets called for every pair
+		for pair := om.Oldest(); pair != nil; pair = om.Oldest() {
+			om.removePair(pair)
+		}
+		return
+	}
+
+	clear(om.pairs)
+	om.list.Init()
+	om.size = 0
+}
+
+// Truncate removes pairs from the newest end of the ordered map until it holds at most n pairs.
+// It's a no-op if the map already holds n pairs or fewer, and is the same as Clear if n <= 0.
+func (om *OrderedMap[K,V]) Truncate(n int) {
+	if n <= 0 {
+		om.Clear()
+		return
+	}
+
+	for om.Len() > n {
+		om.removePair(om.Newest())
+	}
+}
+
+// Shrink reallocates the ordered map's index to fit its current size. Go maps never release
+// memory as they shrink, so this can be useful for long-lived maps whose size dropped significantly.
+// Order and pairs are preserved.
+func (om *OrderedMap[K,V]) Shrink() {
+	if om.pairs == nil {
+		// a zero value has nothing to shrink, and must be left for initialize to set up
+		return
+	}
+	om.resize(om.Len())
+}
+
+// Grow makes sure that the ordered map's index can hold n more pairs without having to grow while
+// they're being set, which helps ahead of bulk loads. If the room it was last sized for, by
+// NewWithCapacity or a previous call to Grow, doesn't suffice, the index is rebuilt, to at least twice
+// that size so that repeated calls stay cheap. Order and pairs are preserved.
+func (om *OrderedMap[K,V]) Grow(n int) {
+	if n <= 0 || om.Len()+n <= om.capacity {
+		return
+	}
+	om.initialize()
+	om.resize(max(om.Len()+n, 2*om.capacity))
+}
+
+// resize rebuilds the ordered map's index, sized for the given number of pairs.
+func (om *OrderedMap[K,V]) resize(capacity int) {
+	pairs := make(map[K]*Pair[K,V], capacity)
+	for key, pair := range om.pairs {
+		pairs[key] = pair
+	}
+	om.pairs = pairs
+	om.capacity = capacity
+}
+
+// SetOnDelete registers a callback to be called with every pair removed from the ordered map,
+// be it through Delete, Clear, or any of the other methods removing pairs; it's not called when
+// a value simply gets overwritten. The callback is called once the pair has been removed.
+// Passing nil unregisters it.
+func (om *OrderedMap[K,V]) SetOnDelete(cb func(key K, value V)) {
+	om.onDelete = cb
+}
+
+// Copy returns a shallow copy of the ordered map: the copy has its own pairs, in the same order,
+// so that it can be modified independently of the original; values, however, are simply assigned.
+func (om *OrderedMap[K,V]) Copy() *OrderedMap[K,V] {
+	c := NewWithCapacity[K,V](om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		c.Set(pair.Key, pair.Value)
+	}
+	return c
+}
+
+// CloneWith returns a copy of the ordered map, with the same keys in the same order, and whose
+// values are copied with copyValue; e.g. to deep-copy values that hold slices or maps.
+func (om *OrderedMap[K,V]) CloneWith(copyValue func(V) V) *OrderedMap[K,V] {
+	c := NewWithCapacity[K,V](om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		c.pushBack(pair.Key, copyValue(pair.Value))
+	}
+	return c
+}
+
+// KeysOnlyClone returns a new ordered map with the same keys in the same order, but all holding
+// V's zero value; e.g. to seed results that should mirror the map's ordering.
+func (om *OrderedMap[K,V]) KeysOnlyClone() *OrderedMap[K,V] {
+	c := NewWithCapacity[K,V](om.Len())
+	var zero V
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		c.pushBack(pair.Key, zero)
+	}
+	return c
+}
+
+// Filter returns a new ordered map holding only the pairs for which pred returns true,
+// in the same order. The original map is left unchanged.
+func (om *OrderedMap[K,V]) Filter(pred func(key K, value V) bool) *OrderedMap[K,V] {
+	filtered := New[K,V]()
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if pred(pair.Key, pair.Value) {
+			filtered.pushBack(pair.Key, pair.Value)
+		}
+	}
+	return filtered
+}
+
+// Merge sets every pair from other into the ordered map, from other's oldest to its newest.
+// As with Set, keys already present in the map get their value overwritten but keep their
+// position, while new keys are appended as the newest pairs, in other's order.
+func (om *OrderedMap[K,V]) Merge(other *OrderedMap[K,V]) {
+	for pair := other.Oldest(); pair != nil; pair = pair.Next() {
+		om.Set(pair.Key, pair.Value)
+	}
+}
+
+// ReplaceAll replaces the ordered map's contents with other's pairs, in other's order, as though
+// calling Clear then Merge. The map stays the same object, so existing references to it see the
+// new pairs; the callback registered with SetOnDelete, if any, is called for the removed ones.
+func (om *OrderedMap[K,V]) ReplaceAll(other *OrderedMap[K,V]) {
+	if other == om {
+		return
+	}
+	om.initialize()
+
+	om.Clear()
+	for pair := other.Oldest(); pair != nil; pair = pair.Next() {
+		om.pushBack(pair.Key, pair.Value)
+	}
+}
+
+// AppendMissing sets the pairs from other whose keys are not already present in the ordered map,
+// appending them in other's order, and returns how many were added. Unlike Merge, it never
+// overwrites existing values, which makes it handy to fill in defaults.
+func (om *OrderedMap[K,V]) AppendMissing(other *OrderedMap[K,V]) int {
+	added := 0
+	for pair := other.Oldest(); pair != nil; pair = pair.Next() {
+		if om.SetIfAbsent(pair.Key, pair.Value) {
+			added++
+		}
+	}
+	return added
+}
+
+// Equal returns whether both ordered maps hold the same pairs in the same order,
+// values being compared with eq.
+func (om *OrderedMap[K,V]) Equal(other *OrderedMap[K,V], eq func(V, V) bool) bool {
+	if om.Len() != other.Len() {
+		return false
+	}
+
+	for pair, otherPair := om.Oldest(), other.Oldest(); pair != nil; pair, otherPair = pair.Next(), otherPair.Next() {
+		if pair.Key != otherPair.Key || !eq(pair.Value, otherPair.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns whether both ordered maps hold the same pairs in the same order, values being
+// compared with ==. It's a function rather than a method because methods can't further constrain
+// their receiver's type parameters; use the Equal method for values that aren't comparable.
+func Equal[K comparable, V comparable](a, b *OrderedMap[K,V]) bool {
+	return a.Equal(b, func(x, y V) bool {
+		return x == y
+	})
+}
+
+// Intersect returns a new ordered map holding the pairs whose keys are present in both
+// ordered maps, with om's values and in om's order.
+func (om *OrderedMap[K,V]) Intersect(other *OrderedMap[K,V]) *OrderedMap[K,V] {
+	return om.Filter(func(key K, _ V) bool {
+		return other.Has(key)
+	})
+}
+
+// Difference returns a new ordered map holding the pairs whose keys are present in om but
+// not in other, with om's values and in om's order.
+func (om *OrderedMap[K,V]) Difference(other *OrderedMap[K,V]) *OrderedMap[K,V] {
+	return om.Filter(func(key K, _ V) bool {
+		return !other.Has(key)
+	})
+}
+
+// Diff compares other, as the newer version, against the ordered map, as the older one. It returns
+// the keys only present in other, in other's order, then those only present in om and those
+// present in both but with values that eq doesn't deem equal, both in om's order.
+func (om *OrderedMap[K,V]) Diff(other *OrderedMap[K,V], eq func(V, V) bool) (added, removed, changed []K) {
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		otherPair := other.GetPair(pair.Key)
+		if otherPair == nil {
+			removed = append(removed, pair.Key)
+		} else if !eq(pair.Value, otherPair.Value) {
+			changed = append(changed, pair.Key)
+		}
+	}
+
+	for pair := other.Oldest(); pair != nil; pair = pair.Next() {
+		if !om.Has(pair.Key) {
+			added = append(added, pair.Key)
+		}
+	}
+	return added, removed, changed
+}
+
+// RenameKey changes the key of the pair with key oldKey to newKey, keeping both its value and
+// its position in the ordered map. It returns false, and leaves the map untouched, if oldKey is
+// not present or if newKey already is.
+func (om *OrderedMap[K,V]) RenameKey(oldKey, newKey K) bool {
+	pair, present := om.pairs[oldKey]
+	if !present {
+		return false
+	}
+	if _, taken := om.pairs[newKey]; taken {
+		return false
+	}
+
+	delete(om.pairs, oldKey)
+	pair.Key = newKey
+	om.pairs[newKey] = pair
+	return true
+}
+
+// MoveToFront moves the pair with the given key to the front of the ordered map,
+// making it the oldest. Its value is left unchanged.
+// It returns false if the key is not present in the map.
+func (om *OrderedMap[K,V]) MoveToFront(key K) bool {
+	pair, present := om.pairs[key]
+	if present {
+		om.list.MoveToFront(pair.element)
+	}
+	return present
+}
+
+// MoveToBack moves the pair with the given key to the back of the ordered map,
+// making it the newest. Its value is left unchanged.
+// It returns false if the key is not present in the map.
+func (om *OrderedMap[K,V]) MoveToBack(key K) bool {
+	pair, present := om.pairs[key]
+	if present {
+		om.list.MoveToBack(pair.element)
+	}
+	return present
+}
+
+// MoveBefore moves the pair with the given key just before the pair with the mark key.
+// Values are left unchanged. It returns false if either key is not present in the map.
+func (om *OrderedMap[K,V]) MoveBefore(key, mark K) bool {
+	pair, markPair, present := om.getPairs(key, mark)
+	if present {
+		om.list.MoveBefore(pair.element, markPair.element)
+	}
+	return present
+}
+
+// MoveAfter moves the pair with the given key just after the pair with the mark key.
+// Values are left unchanged. It returns false if either key is not present in the map.
+func (om *OrderedMap[K,V]) MoveAfter(key, mark K) bool {
+	pair, markPair, present := om.getPairs(key, mark)
+	if present {
+		om.list.MoveAfter(pair.element, markPair.element)
+	}
+	return present
+}
+
+// MoveBy moves the pair with the given key offset positions towards the back of the ordered map if
+// offset is positive, or towards its front if it's negative, stopping at either end. Its value is
+// left unchanged. It returns false if the key is not present in the map.
+func (om *OrderedMap[K,V]) MoveBy(key K, offset int) bool {
+	pair, present := om.pairs[key]
+	if !present {
+		return false
+	}
+
+	mark := pair.element
+	if offset > 0 {
+		for ; offset > 0 && mark.Next() != nil; offset-- {
+			mark = mark.Next()
+		}
+		om.list.MoveAfter(pair.element, mark)
+	} else {
+		for ; offset < 0 && mark.Prev() != nil; offset++ {
+			mark = mark.Prev()
+		}
+		om.list.MoveBefore(pair.element, mark)
+	}
+	return true
+}
+
+// Swap exchanges the positions of the pairs with keys a and b in the ordered map.
+// Keys keep their values. It returns false if either key is not present in the map.
+func (om *OrderedMap[K,V]) Swap(a, b K) bool {
+	pairA, pairB, present := om.getPairs(a, b)
+	if !present || pairA == pairB {
+		return present
+	}
+
+	prevA := pairA.element.Prev()
+	if prevA == pairB.element {
+		// b is right before a
+		om.list.MoveBefore(pairA.element, pairB.element)
+		return true
+	}
+
+	om.list.MoveAfter(pairA.element, pairB.element)
+	if prevA == nil {
+		om.list.MoveToFront(pairB.element)
+	} else {
+		om.list.MoveAfter(pairB.element, prevA)
+	}
+	return true
+}
+
+// Reverse reverses the order of the ordered map in place, so that the oldest pair becomes the newest
+// and vice versa. Keys keep their values.
+func (om *OrderedMap[K,V]) Reverse() {
+	for element := om.list.Front(); element != nil; {
+		next := element.Next()
+		om.list.MoveToFront(element)
+		element = next
+	}
+}
+
+// Rotate shifts the order of the ordered map by n positions in place: if n is positive, the n
+// oldest pairs are moved to the back, in the same order, so that after Rotate(1) the former oldest
+// pair is the newest; if it's negative, the -n newest pairs are moved to the front. n is taken
+// modulo the map's length, and whichever end makes for the fewest moves is used.
+func (om *OrderedMap[K,V]) Rotate(n int) {
+	length := om.Len()
+	if length == 0 {
+		return
+	}
+	n %= length
+	if n < 0 {
+		n += length
+	}
+
+	if n <= length/2 {
+		for ; n > 0; n-- {
+			om.list.MoveToBack(om.list.Front())
+		}
+	} else {
+		for n = length - n; n > 0; n-- {
+			om.list.MoveToFront(om.list.Back())
+		}
+	}
+}
+
+// SortKeys re-orders the ordered map in place, so that its keys are sorted according to less.
+// The sort is stable.
+func (om *OrderedMap[K,V]) SortKeys(less func(a, b K) bool) {
+	om.sortPairs(func(a, b *Pair[K,V]) bool {
+		return less(a.Key, b.Key)
+	})
+}
+
+// SortValues re-orders the ordered map in place, so that its values are sorted according to less.
+// The sort is stable, i.e. pairs with equal values keep their relative order.
+func (om *OrderedMap[K,V]) SortValues(less func(a, b V) bool) {
+	om.sortPairs(func(a, b *Pair[K,V]) bool {
+		return less(a.Value, b.Value)
+	})
+}
+
+// sortPairs re-orders the ordered map's list according to less; pairs and list elements are left untouched.
+func (om *OrderedMap[K,V]) sortPairs(less func(a, b *Pair[K,V]) bool) {
+	pairs := make([]*Pair[K,V], 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		pairs = append(pairs, pair)
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return less(pairs[i], pairs[j])
+	})
+
+	for _, pair := range pairs {
+		om.list.MoveToBack(pair.element)
+	}
+}
+
+// getPairs looks up the pairs for both given keys; the boolean it returns is true only if both are present.
+func (om *OrderedMap[K,V]) getPairs(a, b K) (*Pair[K,V], *Pair[K,V], bool) {
+	pairA, presentA := om.pairs[a]
+	pairB, presentB := om.pairs[b]
+	return pairA, pairB, presentA && presentB
+}
+
 // Len returns the length of the ordered map.
 func (om *OrderedMap[K,V]) Len() int {
-	return len(om.pairs)
+	return om.size
+}
+
+// Keys returns the ordered map's keys, from the oldest to the newest.
+func (om *OrderedMap[K,V]) Keys() []K {
+	keys := make([]K, 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		keys = append(keys, pair.Key)
+	}
+	return keys
+}
+
+// Values returns the ordered map's values, from the oldest to the newest,
+// i.e. in the same order as the keys returned by Keys.
+func (om *OrderedMap[K,V]) Values() []V {
+	values := make([]V, 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		values = append(values, pair.Value)
+	}
+	return values
+}
+
+// Pairs returns copies of the ordered map's pairs, from the oldest to the newest.
+// The copies are detached from the map: they can't be used to iterate over it, and
+// modifying them doesn't affect it.
+func (om *OrderedMap[K,V]) Pairs() []Pair[K,V] {
+	pairs := make([]Pair[K,V], 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		pairs = append(pairs, Pair[K,V]{Key: pair.Key, Value: pair.Value})
+	}
+	return pairs
+}
+
+// Elements returns the elements of the list backing the ordered map, from the oldest to the newest,
+// each holding one of the map's pairs as its value. Those are the map's own elements, not copies:
+// reading them is fine for advanced traversal, but changing their links, or the pairs' keys,
+// corrupts the map, which CheckInvariants may then reveal.
+func (om *OrderedMap[K,V]) Elements() []*list.Element[*Pair[K,V]] {
+	elements := make([]*list.Element[*Pair[K,V]], 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		elements = append(elements, pair.element)
+	}
+	return elements
+}
+
+// Entries returns the ordered map's pairs as plain key-value structs, from the oldest to the newest.
+// Unlike Pairs, it doesn't expose the Pair type at all, which makes it convenient to hand over to
+// code expecting a `[]struct{ Key K; Value V }`.
+func (om *OrderedMap[K,V]) Entries() []struct {
+	Key   K
+	Value V
+} {
+	entries := make([]struct {
+		Key   K
+		Value V
+	}, 0, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		entries = append(entries, struct {
+			Key   K
+			Value V
+		}{pair.Key, pair.Value})
+	}
+	return entries
+}
+
+// String returns a human-readable representation of the ordered map, e.g. `OrderedMap[foo:bar, bar:baz]`,
+// with pairs listed from the oldest to the newest.
+func (om *OrderedMap[K,V]) String() string {
+	var builder strings.Builder
+	builder.WriteString("OrderedMap[")
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if pair != om.Oldest() {
+			builder.WriteString(", ")
+		}
+		fmt.Fprintf(&builder, "%v:%v", pair.Key, pair.Value)
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
+
+// ToMap returns a regular Go map holding the same pairs as the ordered map, minus the ordering.
+func (om *OrderedMap[K,V]) ToMap() map[K]V {
+	m := make(map[K]V, om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		m[pair.Key] = pair.Value
+	}
+	return m
 }
 
 // Oldest returns a pointer to the oldest pair. It's meant to be used to iterate on the ordered map's
 // pairs from the oldest to the newest, e.g.:
 // for pair := orderedMap.Oldest(); pair != nil; pair = pair.Next() { fmt.Printf("%v => %v\n", pair.Key, pair.Value) }
 func (om *OrderedMap[K,V]) Oldest() *Pair[K,V] {
+	if om.list == nil {
+		// a zero value, which is empty
+		return nil
+	}
 	// cannot use om.list.Front() (value of type *list.Element[Pair[K, V]]) as type *list.Element[*Pair[K, V]] in argument to listElementToPair[K, V]
 	return listElementToPair[K,V](om.list.Front())
 }
@@ -99,19 +959,121 @@ func (om *OrderedMap[K,V]) Oldest() *Pair[K,V] {
 // pairs from the newest to the oldest, e.g.:
 // for pair := orderedMap.Oldest(); pair != nil; pair = pair.Next() { fmt.Printf("%v => %v\n", pair.Key, pair.Value) }
 func (om *OrderedMap[K,V]) Newest() *Pair[K,V] {
+	if om.list == nil {
+		return nil
+	}
 	return listElementToPair[K,V](om.list.Back())
 }
 
-// Next returns a pointer to the next pair.
+// At returns a pointer to the pair at the given position in the ordered map, or nil if the index
+// is out of range. Negative indices count back from the newest pair, i.e. -1 is the newest pair.
+// It's linear in the distance to the closest end of the map, as it walks from that end.
+func (om *OrderedMap[K,V]) At(index int) *Pair[K,V] {
+	length := om.Len()
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return nil
+	}
+
+	if index < length/2 {
+		pair := om.Oldest()
+		for i := 0; i < index; i++ {
+			pair = pair.Next()
+		}
+		return pair
+	}
+
+	pair := om.Newest()
+	for i := length - 1; i > index; i-- {
+		pair = pair.Prev()
+	}
+	return pair
+}
+
+// OldestN returns the n oldest pairs, from the oldest to the newest, or all of them
+// if the ordered map holds fewer than n pairs.
+func (om *OrderedMap[K,V]) OldestN(n int) []*Pair[K,V] {
+	pairs := make([]*Pair[K,V], 0, min(max(n, 0), om.Len()))
+	for pair := om.Oldest(); pair != nil && len(pairs) < n; pair = pair.Next() {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// NewestN returns the n newest pairs, from the newest to the oldest, or all of them
+// if the ordered map holds fewer than n pairs.
+func (om *OrderedMap[K,V]) NewestN(n int) []*Pair[K,V] {
+	pairs := make([]*Pair[K,V], 0, min(max(n, 0), om.Len()))
+	for pair := om.Newest(); pair != nil && len(pairs) < n; pair = pair.Prev() {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// Index returns the zero-based position of the given key in the ordered map, or -1 if it's not present.
+// Unlike most other operations, it's linear in the key's position, as it walks from the oldest pair.
+func (om *OrderedMap[K,V]) Index(key K) int {
+	target, present := om.pairs[key]
+	if !present {
+		return -1
+	}
+
+	index := 0
+	for pair := om.Oldest(); pair != target; pair = pair.Next() {
+		index++
+	}
+	return index
+}
+
+// Next returns a pointer to the next pair, or nil if there's none,
+// including for pairs that don't belong to a map, such as those returned by Pairs.
 func (p *Pair[K,V]) Next() *Pair[K,V] {
+	if p.element == nil {
+		return nil
+	}
 	return listElementToPair[K,V](p.element.Next())
 }
 
-// Previous returns a pointer to the previous pair.
+// Previous returns a pointer to the previous pair, or nil if there's none,
+// including for pairs that don't belong to a map, such as those returned by Pairs.
 func (p *Pair[K,V]) Prev() *Pair[K,V] {
+	if p.element == nil {
+		return nil
+	}
 	return listElementToPair[K,V](p.element.Prev())
 }
 
+// CheckInvariants verifies the ordered map's internal consistency, and returns an error describing
+// the first problem found, if any. It's linear in the size of the map, and is meant to be used
+// in tests or while debugging, e.g. to catch corruption caused by unsynchronized concurrent accesses.
+func (om *OrderedMap[K,V]) CheckInvariants() error {
+	if len(om.pairs) != om.list.Len() {
+		return fmt.Errorf("orderedmap: index has %d pairs, but list has %d elements", len(om.pairs), om.list.Len())
+	}
+	if om.size != len(om.pairs) {
+		return fmt.Errorf("orderedmap: size is %d, but index has %d pairs", om.size, len(om.pairs))
+	}
+
+	for element := om.list.Front(); element != nil; element = element.Next() {
+		pair := element.Value
+		if pair == nil {
+			return fmt.Errorf("orderedmap: list element with nil pair")
+		}
+		if pair.element != element {
+			return fmt.Errorf("orderedmap: pair with key %v doesn't point back to its list element", pair.Key)
+		}
+		if indexed, present := om.pairs[pair.Key]; !present {
+			return fmt.Errorf("orderedmap: key %v is in the list but not in the index", pair.Key)
+		} else if indexed != pair {
+			return fmt.Errorf("orderedmap: key %v is indexed with a different pair than the one in the list", pair.Key)
+		}
+	}
+
+	return nil
+}
+
 func listElementToPair[K comparable, V any](element *list.Element[*Pair[K,V]]) *Pair[K,V] {
 	if element == nil {
 		return nil
@@ -2,6 +2,7 @@ package orderedmap
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -171,6 +172,1363 @@ func TestEmptyMapOperations(t *testing.T) {
 	assert.Nil(t, om.Newest())
 }
 
+func TestMustGet(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	assert.Equal(t, 1, om.MustGet("foo"))
+
+	defer func() {
+		err, ok := recover().(error)
+		if assert.True(t, ok) {
+			assert.True(t, errors.Is(err, ErrKeyNotFound))
+			assert.Equal(t, "orderedmap: key not found: bar", err.Error())
+		}
+	}()
+	om.MustGet("bar")
+	t.Fatal("should have panicked")
+}
+
+func TestPeek(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	value, present := om.Peek("foo")
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+
+	value, present = om.Peek("baz")
+	assert.Empty(t, value)
+	assert.False(t, present)
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{1, 2})
+}
+
+func TestGetPairOk(t *testing.T) {
+	om := New[string, *int]()
+	om.Set("foo", nil)
+
+	pair, present := om.GetPairOk("foo")
+	assert.True(t, present)
+	if assert.NotNil(t, pair) {
+		assert.Equal(t, "foo", pair.Key)
+		assert.Nil(t, pair.Value)
+		assert.Same(t, om.GetPair("foo"), pair)
+	}
+
+	pair, present = om.GetPairOk("bar")
+	assert.False(t, present)
+	assert.Nil(t, pair)
+}
+
+func TestGetOrDefault(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	assert.Equal(t, 1, om.GetOrDefault("foo", 12))
+	assert.Equal(t, 12, om.GetOrDefault("bar", 12))
+	assert.False(t, om.Has("bar"))
+	assertLenEqual(t, om, 1)
+}
+
+func TestReplaceValue(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	oldValue, err := om.ReplaceValue("foo", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, oldValue)
+
+	oldValue, err = om.ReplaceValue("baz", 3)
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	assert.Empty(t, oldValue)
+	assert.False(t, om.Has("baz"))
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{10, 2})
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	assert.False(t, om.CompareAndSwap("foo", 2, 10, eq))
+	assert.False(t, om.CompareAndSwap("baz", 0, 10, eq))
+	assert.False(t, om.Has("baz"))
+
+	assert.True(t, om.CompareAndSwap("foo", 1, 10, eq))
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{10, 2})
+	assert.False(t, om.CompareAndSwap("foo", 1, 20, eq))
+}
+
+func TestSetChanged(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	om := New[string, int]()
+
+	assert.True(t, om.SetChanged("foo", 1, eq))
+	assert.False(t, om.SetChanged("foo", 1, eq))
+	assert.True(t, om.SetChanged("foo", 2, eq))
+	assert.True(t, om.SetChanged("bar", 0, eq))
+	assert.False(t, om.SetChanged("bar", 0, eq))
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{2, 0})
+}
+
+func TestSetAll(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	om.SetAll([]Pair[string, int]{
+		{Key: "bar", Value: 2},
+		{Key: "foo", Value: 10},
+		{Key: "baz", Value: 3},
+		{Key: "bar", Value: 20},
+	})
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz"}, []int{10, 20, 3})
+
+	om.SetAll(nil)
+	assertLenEqual(t, om, 3)
+}
+
+func TestSetFront(t *testing.T) {
+	om := New[string, int]()
+
+	oldValue, present := om.SetFront("foo", 1)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+	om.SetFront("bar", 2)
+	om.Set("baz", 3)
+	om.SetFront("qux", 4)
+	assertOrderedPairsEqual[string, int](t, om, []string{"qux", "bar", "foo", "baz"}, []int{4, 2, 1, 3})
+
+	// existing keys stay where they are
+	oldValue, present = om.SetFront("foo", 10)
+	assert.Equal(t, 1, oldValue)
+	assert.True(t, present)
+	assertOrderedPairsEqual[string, int](t, om, []string{"qux", "bar", "foo", "baz"}, []int{4, 2, 10, 3})
+}
+
+func TestSetAndMove(t *testing.T) {
+	om := New[string, int]()
+
+	oldValue, present := om.SetAndMove("foo", 1)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+	om.SetAndMove("bar", 2)
+	om.SetAndMove("baz", 3)
+
+	oldValue, present = om.SetAndMove("foo", 10)
+	assert.Equal(t, 1, oldValue)
+	assert.True(t, present)
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo"}, []int{2, 3, 10})
+
+	om.SetAndMove("foo", 11)
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo"}, []int{2, 3, 11})
+}
+
+func TestGetOrSet(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	value, present := om.GetOrSet("foo", 12)
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+
+	value, present = om.GetOrSet("bar", 2)
+	assert.Equal(t, 2, value)
+	assert.False(t, present)
+
+	value, present = om.GetOrSet("bar", 3)
+	assert.Equal(t, 2, value)
+	assert.True(t, present)
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{1, 2})
+}
+
+func TestGetOrCompute(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	calls := 0
+	compute := func() int {
+		calls++
+		return 12
+	}
+
+	assert.Equal(t, 1, om.GetOrCompute("foo", compute))
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, 12, om.GetOrCompute("bar", compute))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, 12, om.GetOrCompute("bar", compute))
+	assert.Equal(t, 1, calls)
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{1, 12})
+}
+
+func TestSetIfAbsent(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+
+	assert.False(t, om.SetIfAbsent("foo", 12))
+	assert.True(t, om.SetIfAbsent("bar", 2))
+	assert.False(t, om.SetIfAbsent("bar", 3))
+
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar"}, []int{1, 2})
+}
+
+func TestInsertAt(t *testing.T) {
+	om := New[string, int]()
+
+	// on an empty map
+	oldValue, present := om.InsertAt(0, "foo", 1)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+
+	om.InsertAt(0, "bar", 2)
+	om.InsertAt(1, "baz", 3)
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo"}, []int{2, 3, 1})
+
+	// past the end
+	om.InsertAt(12, "qux", 4)
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo", "qux"}, []int{2, 3, 1, 4})
+
+	// negative indices
+	om.InsertAt(-1, "a", 5)
+	om.InsertAt(-12, "b", 6)
+	assertOrderedPairsEqual[string, int](t, om,
+		[]string{"b", "bar", "baz", "foo", "a", "qux"},
+		[]int{6, 2, 3, 1, 5, 4})
+
+	// existing keys don't move
+	oldValue, present = om.InsertAt(0, "foo", 10)
+	assert.Equal(t, 1, oldValue)
+	assert.True(t, present)
+	assertOrderedPairsEqual[string, int](t, om,
+		[]string{"b", "bar", "baz", "foo", "a", "qux"},
+		[]int{6, 2, 3, 10, 5, 4})
+	assertLenEqual(t, om, 6)
+}
+
+func TestInsertBeforeAndAfter(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	oldValue, present := om.InsertBefore("bar", "baz", 3)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "baz", "bar"}, []int{1, 3, 2})
+
+	oldValue, present = om.InsertAfter("foo", "qux", 4)
+	assert.Empty(t, oldValue)
+	assert.False(t, present)
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "qux", "baz", "bar"}, []int{1, 4, 3, 2})
+
+	om.InsertBefore("foo", "a", 5)
+	om.InsertAfter("bar", "z", 6)
+	assertOrderedPairsEqual[string, int](t, om, []string{"a", "foo", "qux", "baz", "bar", "z"}, []int{5, 1, 4, 3, 2, 6})
+
+	// existing keys are updated in place
+	oldValue, present = om.InsertAfter("z", "foo", 10)
+	assert.Equal(t, 1, oldValue)
+	assert.True(t, present)
+	assertOrderedPairsEqual[string, int](t, om, []string{"a", "foo", "qux", "baz", "bar", "z"}, []int{5, 10, 4, 3, 2, 6})
+
+	// absent marks mean appending
+	om.InsertBefore("i dont exist", "b", 7)
+	om.InsertAfter("i dont exist", "c", 8)
+	assertOrderedPairsEqual[string, int](t, om, []string{"a", "foo", "qux", "baz", "bar", "z", "b", "c"}, []int{5, 10, 4, 3, 2, 6, 7, 8})
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"foo": 1, "bar": 2, "baz": 3}
+
+	om := FromMap(m, []string{"baz", "foo", "bar"})
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "foo", "bar"}, []int{3, 1, 2})
+
+	// missing and duplicate keys in the order, and map keys missing from it
+	om = FromMap(m, []string{"bar", "i dont exist", "baz", "bar"})
+	assertLenEqual(t, om, 3)
+	assert.Equal(t, []string{"bar", "baz", "foo"}, om.Keys())
+	assert.Equal(t, []int{2, 3, 1}, om.Values())
+
+	om = FromMap(m, nil)
+	assertLenEqual(t, om, 3)
+	assert.ElementsMatch(t, []string{"foo", "bar", "baz"}, om.Keys())
+}
+
+func TestHas(t *testing.T) {
+	om := New[string, *int]()
+	assert.False(t, om.Has("foo"))
+
+	om.Set("foo", nil)
+	assert.True(t, om.Has("foo"))
+	assert.False(t, om.Has("bar"))
+
+	om.Delete("foo")
+	assert.False(t, om.Has("foo"))
+}
+
+func TestSetOnDelete(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	var deleted []int
+	om.SetOnDelete(func(key, value int) {
+		assert.Equal(t, 2*key, value)
+		assert.False(t, om.Has(key))
+		deleted = append(deleted, key)
+	})
+
+	om.Delete(3)
+	om.Delete(3)
+	assert.Equal(t, []int{3}, deleted)
+
+	// overwriting a value is not a deletion
+	om.Set(4, 8)
+	assert.Equal(t, []int{3}, deleted)
+
+	om.PopOldest()
+	om.PopNewest()
+	assert.Equal(t, []int{3, 0, 9}, deleted)
+
+	om.DeleteFunc(func(key, _ int) bool { return key%2 == 0 })
+	assert.Equal(t, []int{3, 0, 9, 2, 4, 6, 8}, deleted)
+
+	om.Clear()
+	assert.Equal(t, []int{3, 0, 9, 2, 4, 6, 8, 1, 5, 7}, deleted)
+	assertLenEqual(t, om, 0)
+
+	// unregistering
+	om.SetOnDelete(nil)
+	om.Set(1, 2)
+	om.Delete(1)
+	assert.Len(t, deleted, 10)
+}
+
+func TestNewWithCapacity(t *testing.T) {
+	om := NewWithCapacity[int, int](10)
+	assertLenEqual(t, om, 0)
+
+	for i := 0; i < 20; i++ {
+		om.Set(i, i*i)
+	}
+	assertLenEqual(t, om, 20)
+	assert.Equal(t, 0, om.Oldest().Key)
+	assert.Equal(t, 19, om.Newest().Key)
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	assert.False(t, om.CompareAndDelete("foo", 2, eq))
+	assert.False(t, om.CompareAndDelete("baz", 0, eq))
+	assertLenEqual(t, om, 2)
+
+	assert.True(t, om.CompareAndDelete("foo", 1, eq))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar"}, []int{2})
+	assert.False(t, om.CompareAndDelete("foo", 1, eq))
+}
+
+func TestDeleteFunc(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	removed := om.DeleteFunc(func(key, value int) bool {
+		return key%3 != 0 || value == 0
+	})
+	assert.Equal(t, 7, removed)
+	assertLenEqual(t, om, 3)
+	assertOrderedPairsEqual[int, int](t, om, []int{3, 6, 9}, []int{6, 12, 18})
+
+	assert.Equal(t, 0, om.DeleteFunc(func(int, int) bool { return false }))
+	assert.Equal(t, 3, om.DeleteFunc(func(int, int) bool { return true }))
+	assertLenEqual(t, om, 0)
+}
+
+func TestDeleteAll(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.Equal(t, 2, om.DeleteAll([]string{"baz", "i dont exist", "foo", "baz"}))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar"}, []int{2})
+
+	assert.Equal(t, 0, om.DeleteAll(nil))
+	assertLenEqual(t, om, 1)
+}
+
+func TestPopOldest(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	key, value, ok := om.PopOldest()
+	assert.Equal(t, "foo", key)
+	assert.Equal(t, 1, value)
+	assert.True(t, ok)
+	assertLenEqual(t, om, 2)
+	_, present := om.Get("foo")
+	assert.False(t, present)
+
+	key, value, ok = om.PopOldest()
+	assert.Equal(t, "bar", key)
+	assert.Equal(t, 2, value)
+	assert.True(t, ok)
+
+	key, value, ok = om.PopOldest()
+	assert.Equal(t, "baz", key)
+	assert.Equal(t, 3, value)
+	assert.True(t, ok)
+	assertLenEqual(t, om, 0)
+
+	key, value, ok = om.PopOldest()
+	assert.Empty(t, key)
+	assert.Empty(t, value)
+	assert.False(t, ok)
+}
+
+func TestPopNewest(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	key, value, ok := om.PopNewest()
+	assert.Equal(t, "bar", key)
+	assert.Equal(t, 2, value)
+	assert.True(t, ok)
+	assertLenEqual(t, om, 1)
+
+	// single element map
+	key, value, ok = om.PopNewest()
+	assert.Equal(t, "foo", key)
+	assert.Equal(t, 1, value)
+	assert.True(t, ok)
+	assertLenEqual(t, om, 0)
+	assert.Nil(t, om.Oldest())
+	assert.Nil(t, om.Newest())
+
+	key, value, ok = om.PopNewest()
+	assert.Empty(t, key)
+	assert.Empty(t, value)
+	assert.False(t, ok)
+}
+
+func TestPopAt(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	value, index, ok := om.PopAt("bar")
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, index)
+	assert.True(t, ok)
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "baz"}, []int{1, 3})
+
+	// undo
+	om.InsertAt(index, "bar", value)
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz"}, []int{1, 2, 3})
+
+	value, index, ok = om.PopAt("baz")
+	assert.Equal(t, 3, value)
+	assert.Equal(t, 2, index)
+	assert.True(t, ok)
+
+	value, index, ok = om.PopAt("i dont exist")
+	assert.Empty(t, value)
+	assert.Equal(t, -1, index)
+	assert.False(t, ok)
+	assertLenEqual(t, om, 2)
+}
+
+func TestDeleteOldestAndNewest(t *testing.T) {
+	om := New[string, int]()
+	assert.False(t, om.DeleteOldest())
+	assert.False(t, om.DeleteNewest())
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.True(t, om.DeleteOldest())
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz"}, []int{2, 3})
+
+	assert.True(t, om.DeleteNewest())
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar"}, []int{2})
+
+	assert.True(t, om.DeleteNewest())
+	assertLenEqual(t, om, 0)
+	assert.False(t, om.DeleteOldest())
+}
+
+func TestClear(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	om.Clear()
+	assertLenEqual(t, om, 0)
+	assert.Nil(t, om.Oldest())
+	assert.Nil(t, om.Newest())
+	_, present := om.Get("foo")
+	assert.False(t, present)
+
+	// it can be reused afterwards
+	om.Set("baz", 3)
+	om.Set("foo", 4)
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "foo"}, []int{3, 4})
+}
+
+func TestTruncate(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	om.Truncate(12)
+	assertLenEqual(t, om, 10)
+
+	om.Truncate(3)
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2}, []int{0, 2, 4})
+
+	om.Truncate(3)
+	assertLenEqual(t, om, 3)
+
+	om.Truncate(0)
+	assertLenEqual(t, om, 0)
+
+	om.Set(1, 1)
+	om.Truncate(-1)
+	assertLenEqual(t, om, 0)
+}
+
+func TestShrink(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		om.Set(i, 2*i)
+	}
+	om.Truncate(3)
+	pair := om.GetPair(1)
+
+	om.Shrink()
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2}, []int{0, 2, 4})
+	assert.Same(t, pair, om.GetPair(1))
+
+	// the map keeps working afterwards
+	om.Set(3, 6)
+	om.Delete(0)
+	assertOrderedPairsEqual[int, int](t, om, []int{1, 2, 3}, []int{2, 4, 6})
+
+	// on a zero value, which can still be decoded into afterwards
+	var zero OrderedMap[string, int]
+	zero.Shrink()
+	if assert.NoError(t, zero.UnmarshalJSON([]byte(`{"b":2,"a":1}`))) {
+		assertOrderedPairsEqual[string, int](t, &zero, []string{"b", "a"}, []int{2, 1})
+	}
+}
+
+func TestGrow(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 3; i++ {
+		om.Set(i, 2*i)
+	}
+	pair := om.GetPair(1)
+
+	om.Grow(1000)
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2}, []int{0, 2, 4})
+	assert.Same(t, pair, om.GetPair(1))
+
+	for i := 3; i < 1000; i++ {
+		om.Set(i, 2*i)
+	}
+	assertLenEqual(t, om, 1000)
+
+	// no-ops
+	om.Grow(0)
+	om.Grow(-1)
+	om.Grow(3)
+	assertLenEqual(t, om, 1000)
+	assert.Equal(t, 1003, om.capacity)
+
+	// the index is only rebuilt when needed, and then at least doubles
+	rebuilds := 0
+	for i := 1000; i < 5000; i++ {
+		capacity := om.capacity
+		om.Grow(1)
+		if om.capacity != capacity {
+			rebuilds++
+		}
+		om.Set(i, 2*i)
+	}
+	assert.Equal(t, 3, rebuilds)
+	assertLenEqual(t, om, 5000)
+
+	sized := NewWithCapacity[int, int](100)
+	sized.Grow(100)
+	assert.Equal(t, 100, sized.capacity)
+	sized.Grow(101)
+	assert.Equal(t, 200, sized.capacity)
+
+	// Shrink resets it
+	om.Truncate(10)
+	om.Shrink()
+	assert.Equal(t, 10, om.capacity)
+
+	var zero OrderedMap[int, int]
+	zero.Grow(10)
+	zero.Set(1, 2)
+	assertOrderedPairsEqual[int, int](t, &zero, []int{1}, []int{2})
+}
+
+func TestCopy(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	c := om.Copy()
+	assertLenEqual(t, c, 3)
+	assertOrderedPairsEqual[string, int](t, c, []string{"foo", "bar", "baz"}, []int{1, 2, 3})
+	assert.NotSame(t, om.GetPair("foo"), c.GetPair("foo"))
+
+	// modifying the copy doesn't affect the original
+	c.Delete("foo")
+	c.Set("bar", 20)
+	c.Set("foo", 10)
+	assertOrderedPairsEqual[string, int](t, c, []string{"bar", "baz", "foo"}, []int{20, 3, 10})
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz"}, []int{1, 2, 3})
+}
+
+func TestCloneWith(t *testing.T) {
+	om := New[string, []int]()
+	om.Set("foo", []int{1, 2})
+	om.Set("bar", []int{3})
+
+	clone := om.CloneWith(func(value []int) []int {
+		return append([]int(nil), value...)
+	})
+	assertOrderedPairsEqual[string, []int](t, clone, []string{"foo", "bar"}, [][]int{{1, 2}, {3}})
+
+	// values are independent
+	clone.MustGet("foo")[0] = 12
+	assert.Equal(t, []int{1, 2}, om.MustGet("foo"))
+
+	// whereas a shallow copy shares them
+	om.Copy().MustGet("foo")[0] = 12
+	assert.Equal(t, []int{12, 2}, om.MustGet("foo"))
+}
+
+func TestKeysOnlyClone(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	clone := om.KeysOnlyClone()
+	assertOrderedPairsEqual[string, int](t, clone, []string{"foo", "bar", "baz"}, []int{0, 0, 0})
+
+	clone.Set("foo", 12)
+	assert.Equal(t, 1, om.MustGet("foo"))
+
+	assertLenEqual(t, New[string, int]().KeysOnlyClone(), 0)
+}
+
+func TestFilter(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 2*i)
+	}
+
+	filtered := om.Filter(func(key, value int) bool {
+		return key%3 == 0 && value != 0
+	})
+	assertOrderedPairsEqual[int, int](t, filtered, []int{3, 6, 9}, []int{6, 12, 18})
+	assertLenEqual(t, om, 10)
+
+	assertLenEqual(t, om.Filter(func(int, int) bool { return false }), 0)
+}
+
+func TestMerge(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	other := New[string, int]()
+	other.Set("baz", 30)
+	other.Set("foo", 10)
+	other.Set("qux", 40)
+
+	om.Merge(other)
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz", "qux"}, []int{10, 2, 30, 40})
+	assertOrderedPairsEqual[string, int](t, other, []string{"baz", "foo", "qux"}, []int{30, 10, 40})
+}
+
+func TestReplaceAll(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	ref := om
+
+	var deleted []string
+	om.SetOnDelete(func(key string, _ int) {
+		deleted = append(deleted, key)
+	})
+
+	other := New[string, int]()
+	other.Set("baz", 30)
+	other.Set("foo", 10)
+
+	om.ReplaceAll(other)
+	assertOrderedPairsEqual[string, int](t, ref, []string{"baz", "foo"}, []int{30, 10})
+	assert.Equal(t, []string{"foo", "bar"}, deleted)
+
+	// the maps are independent
+	other.Set("qux", 40)
+	assertLenEqual(t, om, 2)
+
+	// replacing with itself is a no-op
+	om.ReplaceAll(om)
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "foo"}, []int{30, 10})
+
+	om.ReplaceAll(New[string, int]())
+	assertLenEqual(t, om, 0)
+
+	var zero OrderedMap[string, int]
+	zero.ReplaceAll(other)
+	assertOrderedPairsEqual[string, int](t, &zero, []string{"baz", "foo", "qux"}, []int{30, 10, 40})
+}
+
+func TestAppendMissing(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	defaults := New[string, int]()
+	defaults.Set("baz", 30)
+	defaults.Set("foo", 10)
+	defaults.Set("qux", 40)
+
+	assert.Equal(t, 2, om.AppendMissing(defaults))
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz", "qux"}, []int{1, 2, 30, 40})
+
+	assert.Equal(t, 0, om.AppendMissing(defaults))
+	assertLenEqual(t, om, 4)
+}
+
+func TestEqual(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	assert.True(t, om.Equal(om.Copy(), eq))
+	assert.True(t, New[string, int]().Equal(New[string, int](), eq))
+
+	// different lengths
+	other := om.Copy()
+	other.Set("baz", 3)
+	assert.False(t, om.Equal(other, eq))
+	assert.False(t, other.Equal(om, eq))
+
+	// different values
+	other = om.Copy()
+	other.Set("bar", 3)
+	assert.False(t, om.Equal(other, eq))
+
+	// different orders
+	other = om.Copy()
+	other.MoveToFront("bar")
+	assert.False(t, om.Equal(other, eq))
+
+	// custom equality
+	other = om.Copy()
+	other.Set("bar", 20)
+	assert.False(t, om.Equal(other, eq))
+	assert.True(t, om.Equal(other, func(a, b int) bool { return a == b || 10*a == b }))
+}
+
+func TestEqualFunc(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	assert.True(t, Equal(om, om.Copy()))
+	assert.True(t, Equal(New[string, int](), New[string, int]()))
+
+	other := om.Copy()
+	other.Set("bar", 3)
+	assert.False(t, Equal(om, other))
+
+	other = om.Copy()
+	other.MoveToFront("bar")
+	assert.False(t, Equal(om, other))
+
+	other = om.Copy()
+	other.Set("baz", 0)
+	assert.False(t, Equal(om, other))
+	assert.False(t, Equal(other, om))
+}
+
+func TestIntersect(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	other := New[string, int]()
+	other.Set("baz", 30)
+	other.Set("qux", 40)
+	other.Set("foo", 10)
+
+	assertOrderedPairsEqual[string, int](t, om.Intersect(other), []string{"foo", "baz"}, []int{1, 3})
+	assertOrderedPairsEqual[string, int](t, other.Intersect(om), []string{"baz", "foo"}, []int{30, 10})
+	assertLenEqual(t, om, 3)
+
+	assertLenEqual(t, om.Intersect(New[string, int]()), 0)
+	assertLenEqual(t, New[string, int]().Intersect(om), 0)
+}
+
+func TestDifference(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Set("qux", 4)
+
+	other := New[string, int]()
+	other.Set("baz", 30)
+	other.Set("quux", 50)
+	other.Set("foo", 10)
+
+	assertOrderedPairsEqual[string, int](t, om.Difference(other), []string{"bar", "qux"}, []int{2, 4})
+	assertOrderedPairsEqual[string, int](t, other.Difference(om), []string{"quux"}, []int{50})
+	assertLenEqual(t, om, 4)
+
+	assertOrderedPairsEqual[string, int](t, om.Difference(New[string, int]()), om.Keys(), om.Values())
+	assertLenEqual(t, om.Difference(om), 0)
+}
+
+func TestDiff(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+
+	old := New[string, int]()
+	old.Set("foo", 1)
+	old.Set("bar", 2)
+	old.Set("baz", 3)
+	old.Set("qux", 4)
+
+	updated := New[string, int]()
+	updated.Set("quux", 50)
+	updated.Set("qux", 40)
+	updated.Set("corge", 60)
+	updated.Set("foo", 1)
+	updated.Set("bar", 20)
+
+	added, removed, changed := old.Diff(updated, eq)
+	assert.Equal(t, []string{"quux", "corge"}, added)
+	assert.Equal(t, []string{"baz"}, removed)
+	assert.Equal(t, []string{"bar", "qux"}, changed)
+
+	// the other way around
+	added, removed, changed = updated.Diff(old, eq)
+	assert.Equal(t, []string{"baz"}, added)
+	assert.Equal(t, []string{"quux", "corge"}, removed)
+	assert.Equal(t, []string{"qux", "bar"}, changed)
+
+	// custom equality
+	_, _, changed = old.Diff(updated, func(a, b int) bool { return a == b || 10*a == b })
+	assert.Empty(t, changed)
+
+	added, removed, changed = old.Diff(old.Copy(), eq)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+}
+
+func TestRenameKey(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.True(t, om.RenameKey("bar", "qux"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "qux", "baz"}, []int{1, 2, 3})
+	assert.False(t, om.Has("bar"))
+	assertLenEqual(t, om, 3)
+
+	assert.False(t, om.RenameKey("i dont exist", "whatever"))
+	assert.False(t, om.RenameKey("foo", "baz"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "qux", "baz"}, []int{1, 2, 3})
+}
+
+func TestMoveToFront(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.True(t, om.MoveToFront("baz"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "foo", "bar"}, []int{3, 1, 2})
+
+	// already at the front
+	assert.True(t, om.MoveToFront("baz"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "foo", "bar"}, []int{3, 1, 2})
+
+	assert.False(t, om.MoveToFront("i dont exist"))
+	assertLenEqual(t, om, 3)
+}
+
+func TestMoveToBack(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.True(t, om.MoveToBack("foo"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo"}, []int{2, 3, 1})
+	assert.Equal(t, "foo", om.Newest().Key)
+
+	// already at the back
+	assert.True(t, om.MoveToBack("foo"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo"}, []int{2, 3, 1})
+
+	assert.False(t, om.MoveToBack("i dont exist"))
+	assertLenEqual(t, om, 3)
+}
+
+func TestMoveBeforeAndAfter(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Set("qux", 4)
+
+	assert.True(t, om.MoveBefore("qux", "bar"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "qux", "bar", "baz"}, []int{1, 4, 2, 3})
+
+	assert.True(t, om.MoveAfter("foo", "baz"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"qux", "bar", "baz", "foo"}, []int{4, 2, 3, 1})
+
+	assert.True(t, om.MoveBefore("bar", "qux"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "qux", "baz", "foo"}, []int{2, 4, 3, 1})
+
+	// moving relative to itself is a no-op
+	assert.True(t, om.MoveAfter("baz", "baz"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "qux", "baz", "foo"}, []int{2, 4, 3, 1})
+
+	assert.False(t, om.MoveBefore("i dont exist", "foo"))
+	assert.False(t, om.MoveAfter("foo", "i dont exist"))
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "qux", "baz", "foo"}, []int{2, 4, 3, 1})
+}
+
+func TestMoveBy(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 5; i++ {
+		om.Set(i, 2*i)
+	}
+
+	assert.True(t, om.MoveBy(1, 2))
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 2, 3, 1, 4}, []int{0, 4, 6, 2, 8})
+
+	assert.True(t, om.MoveBy(4, -1))
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 2, 3, 4, 1}, []int{0, 4, 6, 8, 2})
+
+	assert.True(t, om.MoveBy(3, 0))
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 2, 3, 4, 1}, []int{0, 4, 6, 8, 2})
+
+	// clamped at either end
+	assert.True(t, om.MoveBy(2, 12))
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 3, 4, 1, 2}, []int{0, 6, 8, 2, 4})
+	assert.True(t, om.MoveBy(4, -12))
+	assertOrderedPairsEqual[int, int](t, om, []int{4, 0, 3, 1, 2}, []int{8, 0, 6, 2, 4})
+	assert.True(t, om.MoveBy(4, -1))
+	assert.True(t, om.MoveBy(2, 1))
+	assertOrderedPairsEqual[int, int](t, om, []int{4, 0, 3, 1, 2}, []int{8, 0, 6, 2, 4})
+
+	assert.False(t, om.MoveBy(12, 1))
+	assertLenEqual(t, om, 5)
+}
+
+func TestSwap(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 5; i++ {
+		om.Set(i, 2*i)
+	}
+
+	for _, testCase := range []struct {
+		a, b     int
+		expected []int
+	}{
+		{0, 4, []int{4, 1, 2, 3, 0}},
+		{4, 0, []int{0, 1, 2, 3, 4}},
+		{1, 2, []int{0, 2, 1, 3, 4}},
+		{1, 2, []int{0, 1, 2, 3, 4}},
+		{3, 1, []int{0, 3, 2, 1, 4}},
+		{0, 3, []int{3, 0, 2, 1, 4}},
+		{2, 2, []int{3, 0, 2, 1, 4}},
+	} {
+		assert.True(t, om.Swap(testCase.a, testCase.b))
+
+		values := make([]int, len(testCase.expected))
+		for i, key := range testCase.expected {
+			values[i] = 2 * key
+		}
+		assertOrderedPairsEqual[int, int](t, om, testCase.expected, values)
+		assertLenEqual(t, om, 5)
+	}
+
+	assert.False(t, om.Swap(0, 12))
+	assert.False(t, om.Swap(12, 0))
+	assertOrderedPairsEqual[int, int](t, om, []int{3, 0, 2, 1, 4}, []int{6, 0, 4, 2, 8})
+}
+
+func TestReverse(t *testing.T) {
+	om := New[string, int]()
+	om.Reverse()
+	assertLenEqual(t, om, 0)
+
+	om.Set("foo", 1)
+	om.Reverse()
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo"}, []int{1})
+
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Reverse()
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "bar", "foo"}, []int{3, 2, 1})
+
+	om.Reverse()
+	assertOrderedPairsEqual[string, int](t, om, []string{"foo", "bar", "baz"}, []int{1, 2, 3})
+}
+
+func TestRotate(t *testing.T) {
+	om := New[int, int]()
+	om.Rotate(3)
+	assertLenEqual(t, om, 0)
+
+	for i := 0; i < 5; i++ {
+		om.Set(i, 2*i)
+	}
+
+	om.Rotate(1)
+	assertOrderedPairsEqual[int, int](t, om, []int{1, 2, 3, 4, 0}, []int{2, 4, 6, 8, 0})
+
+	om.Rotate(-1)
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2, 3, 4}, []int{0, 2, 4, 6, 8})
+
+	om.Rotate(4)
+	assertOrderedPairsEqual[int, int](t, om, []int{4, 0, 1, 2, 3}, []int{8, 0, 2, 4, 6})
+
+	om.Rotate(-3)
+	assertOrderedPairsEqual[int, int](t, om, []int{1, 2, 3, 4, 0}, []int{2, 4, 6, 8, 0})
+
+	// modulo the length
+	om.Rotate(12)
+	assertOrderedPairsEqual[int, int](t, om, []int{3, 4, 0, 1, 2}, []int{6, 8, 0, 2, 4})
+	om.Rotate(-8)
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2, 3, 4}, []int{0, 2, 4, 6, 8})
+	om.Rotate(5)
+	om.Rotate(0)
+	assertOrderedPairsEqual[int, int](t, om, []int{0, 1, 2, 3, 4}, []int{0, 2, 4, 6, 8})
+}
+
+func TestSortKeys(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Set("qux", 4)
+	pair := om.GetPair("baz")
+
+	om.SortKeys(func(a, b string) bool { return a < b })
+	assertOrderedPairsEqual[string, int](t, om, []string{"bar", "baz", "foo", "qux"}, []int{2, 3, 1, 4})
+	assert.Same(t, pair, om.GetPair("baz"))
+
+	om.SortKeys(func(a, b string) bool { return a > b })
+	assertOrderedPairsEqual[string, int](t, om, []string{"qux", "foo", "baz", "bar"}, []int{4, 1, 3, 2})
+
+	// stability
+	om.SortKeys(func(a, b string) bool { return a[0] < b[0] })
+	assertOrderedPairsEqual[string, int](t, om, []string{"baz", "bar", "foo", "qux"}, []int{3, 2, 1, 4})
+}
+
+func TestSortValues(t *testing.T) {
+	counts := New[string, int]()
+	counts.Set("the", 12)
+	counts.Set("cat", 3)
+	counts.Set("sat", 1)
+	counts.Set("on", 3)
+	counts.Set("mat", 1)
+
+	counts.SortValues(func(a, b int) bool { return a > b })
+	assertOrderedPairsEqual[string, int](t, counts,
+		[]string{"the", "cat", "on", "sat", "mat"},
+		[]int{12, 3, 3, 1, 1})
+
+	counts.SortValues(func(a, b int) bool { return a < b })
+	assertOrderedPairsEqual[string, int](t, counts,
+		[]string{"sat", "mat", "cat", "on", "the"},
+		[]int{1, 1, 3, 3, 12})
+}
+
+func TestKeys(t *testing.T) {
+	om := New[string, int]()
+	keys := om.Keys()
+	assert.NotNil(t, keys)
+	assert.Empty(t, keys)
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Delete("bar")
+	om.Set("bar", 4)
+
+	assert.Equal(t, []string{"foo", "baz", "bar"}, om.Keys())
+}
+
+func TestValues(t *testing.T) {
+	om := New[string, int]()
+	values := om.Values()
+	assert.NotNil(t, values)
+	assert.Empty(t, values)
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Set("foo", 4)
+
+	assert.Equal(t, []int{4, 2, 3}, om.Values())
+
+	keys := om.Keys()
+	for i, value := range om.Values() {
+		expected, _ := om.Get(keys[i])
+		assert.Equal(t, expected, value)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	om := New[string, int]()
+	assert.Equal(t, map[string]int{}, om.ToMap())
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	m := om.ToMap()
+	assert.Equal(t, map[string]int{"foo": 1, "bar": 2}, m)
+
+	// the map is independent from the ordered map
+	m["baz"] = 3
+	assertLenEqual(t, om, 2)
+
+	assert.Equal(t, om.Keys(), FromMap(om.ToMap(), om.Keys()).Keys())
+}
+
+func TestPairs(t *testing.T) {
+	om := New[string, int]()
+	pairs := om.Pairs()
+	assert.NotNil(t, pairs)
+	assert.Empty(t, pairs)
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	pairs = om.Pairs()
+	assert.Equal(t, []Pair[string, int]{{Key: "foo", Value: 1}, {Key: "bar", Value: 2}}, pairs)
+	for _, pair := range pairs {
+		assert.Nil(t, pair.element)
+		assert.Nil(t, pair.Next())
+		assert.Nil(t, pair.Prev())
+	}
+
+	// the copies are detached from the map
+	pairs[0].Value = 12
+	value, _ := om.Get("foo")
+	assert.Equal(t, 1, value)
+
+	// and round trip through SetAll
+	other := New[string, int]()
+	other.SetAll(om.Pairs())
+	assertOrderedPairsEqual[string, int](t, other, []string{"foo", "bar"}, []int{1, 2})
+}
+
+func TestElements(t *testing.T) {
+	om := New[string, int]()
+	assert.Empty(t, om.Elements())
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	elements := om.Elements()
+	if assert.Len(t, elements, 3) {
+		for i, key := range []string{"foo", "bar", "baz"} {
+			assert.Same(t, om.GetPair(key), elements[i].Value)
+		}
+		assert.Same(t, elements[1], elements[0].Next())
+		assert.Nil(t, elements[2].Next())
+	}
+
+	// updating values through them is visible to the map
+	elements[0].Value.Value = 12
+	assert.Equal(t, 12, om.MustGet("foo"))
+	assert.NoError(t, om.CheckInvariants())
+}
+
+func TestEntries(t *testing.T) {
+	om := New[string, int]()
+	assert.NotNil(t, om.Entries())
+	assert.Empty(t, om.Entries())
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+
+	var entries []struct {
+		Key   string
+		Value int
+	} = om.Entries()
+	assert.Equal(t, []struct {
+		Key   string
+		Value int
+	}{{"foo", 1}, {"bar", 2}}, entries)
+}
+
+func TestString(t *testing.T) {
+	om := New[string, any]()
+	assert.Equal(t, "OrderedMap[]", om.String())
+
+	om.Set("foo", "bar")
+	om.Set("bar", 12)
+	om.Set("baz", []int{1, 2})
+	assert.Equal(t, "OrderedMap[foo:bar, bar:12, baz:[1 2]]", om.String())
+	assert.Equal(t, "OrderedMap[foo:bar, bar:12, baz:[1 2]]", fmt.Sprintf("%v", om))
+}
+
+func TestAt(t *testing.T) {
+	om := New[string, int]()
+	assert.Nil(t, om.At(0))
+	assert.Nil(t, om.At(-1))
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	for i, key := range []string{"foo", "bar", "baz"} {
+		if pair := om.At(i); assert.NotNil(t, pair) {
+			assert.Equal(t, key, pair.Key)
+		}
+		if pair := om.At(i - 3); assert.NotNil(t, pair) {
+			assert.Equal(t, key, pair.Key)
+		}
+	}
+
+	assert.Nil(t, om.At(3))
+	assert.Nil(t, om.At(-4))
+
+	// walking from either end
+	for i := 3; i < 100; i++ {
+		om.Set(fmt.Sprintf("key%d", i), i)
+	}
+	for i := 3; i < 100; i++ {
+		if pair := om.At(i); assert.NotNil(t, pair) {
+			assert.Equal(t, i, pair.Value)
+		}
+		if pair := om.At(i - 100); assert.NotNil(t, pair) {
+			assert.Equal(t, i, pair.Value)
+		}
+	}
+}
+
+func TestOldestNAndNewestN(t *testing.T) {
+	om := New[string, int]()
+	assert.Empty(t, om.OldestN(2))
+	assert.Empty(t, om.NewestN(2))
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	keys := func(pairs []*Pair[string, int]) []string {
+		keys := make([]string, len(pairs))
+		for i, pair := range pairs {
+			keys[i] = pair.Key
+		}
+		return keys
+	}
+
+	assert.Equal(t, []string{"foo", "bar"}, keys(om.OldestN(2)))
+	assert.Equal(t, []string{"baz", "bar"}, keys(om.NewestN(2)))
+	assert.Equal(t, []string{"foo", "bar", "baz"}, keys(om.OldestN(12)))
+	assert.Equal(t, []string{"baz", "bar", "foo"}, keys(om.NewestN(12)))
+	assert.Empty(t, om.OldestN(0))
+	assert.Empty(t, om.NewestN(-1))
+
+	assert.Same(t, om.GetPair("baz"), om.NewestN(1)[0])
+}
+
+func TestIndex(t *testing.T) {
+	om := New[string, int]()
+	assert.Equal(t, -1, om.Index("foo"))
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	assert.Equal(t, 0, om.Index("foo"))
+	assert.Equal(t, 1, om.Index("bar"))
+	assert.Equal(t, 2, om.Index("baz"))
+	assert.Equal(t, -1, om.Index("i dont exist"))
+
+	om.Delete("foo")
+	assert.Equal(t, 0, om.Index("bar"))
+	assert.Equal(t, -1, om.Index("foo"))
+}
+
+func TestCheckInvariants(t *testing.T) {
+	om := New[string, int]()
+	assert.NoError(t, om.CheckInvariants())
+
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+	om.Delete("bar")
+	assert.NoError(t, om.CheckInvariants())
+
+	// now let's corrupt it
+	corrupted := om.Copy()
+	delete(corrupted.pairs, "foo")
+	assertErrorContains(t, corrupted.CheckInvariants(), "index has 1 pairs, but list has 2 elements")
+
+	corrupted = om.Copy()
+	corrupted.pairs["foo"] = &Pair[string, int]{Key: "foo", Value: 1}
+	assertErrorContains(t, corrupted.CheckInvariants(), "indexed with a different pair")
+
+	corrupted = om.Copy()
+	corrupted.pairs["qux"] = corrupted.pairs["foo"]
+	delete(corrupted.pairs, "foo")
+	assertErrorContains(t, corrupted.CheckInvariants(), "key foo is in the list but not in the index")
+
+	corrupted = om.Copy()
+	corrupted.size++
+	assertErrorContains(t, corrupted.CheckInvariants(), "size is 3, but index has 2 pairs")
+
+	corrupted = om.Copy()
+	corrupted.GetPair("baz").element = corrupted.GetPair("foo").element
+	assertErrorContains(t, corrupted.CheckInvariants(), "doesn't point back to its list element")
+}
+
 type dummyTestStruct struct {
 	value string
 }
@@ -225,14 +1583,52 @@ func TestShuffle(t *testing.T) {
 	}
 }
 
+func TestRemovedPairsStayIntact(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 5; i++ {
+		om.Set(i, 2*i)
+	}
+	pair := om.GetPair(2)
+	oldest := om.OldestN(2)
+
+	// pairs handed out before being removed keep their own key and value,
+	// even once new pairs come along, and no longer link to the map
+	om.Delete(2)
+	om.DeleteAll([]int{0, 1})
+	om.Set(99, 99)
+	om.Set(100, 100)
+
+	assert.Equal(t, 2, pair.Key)
+	assert.Equal(t, 4, pair.Value)
+	assert.Nil(t, pair.Next())
+	assert.Nil(t, pair.Prev())
+	assert.Equal(t, 0, oldest[0].Key)
+	assert.Equal(t, 1, oldest[1].Key)
+	assertOrderedPairsEqual[int, int](t, om, []int{3, 4, 99, 100}, []int{6, 8, 99, 100})
+}
+
+func BenchmarkChurn(b *testing.B) {
+	om := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		om.Set(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		om.Set(1000+i, i)
+		om.Delete(i)
+	}
+}
+
 /* Test helpers */
 
-func assertOrderedPairsEqual[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys, expectedValues []V) {
+func assertOrderedPairsEqual[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys []K, expectedValues []V) {
 	assertOrderedPairsEqualFromNewest[K,V](t, om, expectedKeys, expectedValues)
 	assertOrderedPairsEqualFromOldest[K,V](t, om, expectedKeys, expectedValues)
 }
 
-func assertOrderedPairsEqualFromNewest[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys, expectedValues []V) {
+func assertOrderedPairsEqualFromNewest[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys []K, expectedValues []V) {
 	if assert.Equal(t, len(expectedKeys), len(expectedValues)) && assert.Equal(t, len(expectedKeys), om.Len()) {
 		i := om.Len() - 1
 		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
@@ -243,13 +1639,13 @@ func assertOrderedPairsEqualFromNewest[K comparable, V any](t *testing.T, om *Or
 	}
 }
 
-func assertOrderedPairsEqualFromOldest[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys, expectedValues []V) {
+func assertOrderedPairsEqualFromOldest[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expectedKeys []K, expectedValues []V) {
 	if assert.Equal(t, len(expectedKeys), len(expectedValues)) && assert.Equal(t, len(expectedKeys), om.Len()) {
-		i := om.Len() - 1
-		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+		i := 0
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
 			assert.Equal(t, expectedKeys[i], pair.Key)
 			assert.Equal(t, expectedValues[i], pair.Value)
-			i--
+			i++
 		}
 	}
 }
@@ -259,6 +1655,13 @@ func assertLenEqual[K comparable, V any](t *testing.T, om *OrderedMap[K,V], expe
 
 	// also check the list length, for good measure
 	assert.Equal(t, expectedLen, om.list.Len())
+	assert.NoError(t, om.CheckInvariants())
+}
+
+func assertErrorContains(t *testing.T, err error, contains string) {
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), contains)
+	}
 }
 
 func randomHexString(t *testing.T, length int) string {
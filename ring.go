@@ -0,0 +1,60 @@
+package orderedmap
+
+import "fmt"
+
+// Ring is an ordered map bounded in size, like a ring buffer: once it's full, setting a new key
+// evicts the oldest pair to make room for it. Unlike LRU, only insertion matters: accessing or
+// updating a pair never changes its position, nor when it gets evicted.
+type Ring[K comparable, V any] struct {
+	om   *OrderedMap[K,V]
+	size int
+}
+
+// NewRing creates a new Ring holding at most size pairs. It panics if size isn't positive.
+func NewRing[K comparable, V any](size int) *Ring[K,V] {
+	if size <= 0 {
+		panic(fmt.Sprintf("orderedmap: invalid ring size %d, must be positive", size))
+	}
+	return &Ring[K,V]{
+		om:   NewWithCapacity[K,V](size),
+		size: size,
+	}
+}
+
+// Get looks for the given key, and returns the value associated with it.
+// The boolean it returns says whether the key is present in the map.
+func (r *Ring[K,V]) Get(key K) (V, bool) {
+	return r.om.Get(key)
+}
+
+// Set sets the key-value pair. Existing keys get their value updated in place; new keys are
+// appended as the newest pair, after evicting the oldest one if the ring is full. It returns the
+// evicted pair, and whether there was one.
+func (r *Ring[K,V]) Set(key K, value V) (evictedKey K, evictedValue V, evicted bool) {
+	if pair := r.om.GetPair(key); pair != nil {
+		pair.Value = value
+		return evictedKey, evictedValue, false
+	}
+
+	if r.om.Len() >= r.size {
+		evictedKey, evictedValue, evicted = r.om.PopOldest()
+	}
+	r.om.pushBack(key, value)
+	return evictedKey, evictedValue, evicted
+}
+
+// Delete removes the key-value pair, and returns what `Get` would have returned
+// on that key prior to the call to `Delete`.
+func (r *Ring[K,V]) Delete(key K) (V, bool) {
+	return r.om.Delete(key)
+}
+
+// Len returns the number of pairs in the map.
+func (r *Ring[K,V]) Len() int {
+	return r.om.Len()
+}
+
+// Keys returns the map's keys, from the oldest to the newest.
+func (r *Ring[K,V]) Keys() []K {
+	return r.om.Keys()
+}
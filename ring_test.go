@@ -0,0 +1,52 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing[string, int](3)
+
+	for i, key := range []string{"a", "b", "c"} {
+		_, _, evicted := r.Set(key, i)
+		assert.False(t, evicted)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, r.Keys())
+
+	// evicts the oldest
+	evictedKey, evictedValue, evicted := r.Set("d", 3)
+	assert.Equal(t, "a", evictedKey)
+	assert.Equal(t, 0, evictedValue)
+	assert.True(t, evicted)
+	assert.Equal(t, []string{"b", "c", "d"}, r.Keys())
+
+	// accessing or updating doesn't change the order, nor evicts
+	value, present := r.Get("b")
+	assert.Equal(t, 1, value)
+	assert.True(t, present)
+	_, _, evicted = r.Set("b", 10)
+	assert.False(t, evicted)
+	assert.Equal(t, []string{"b", "c", "d"}, r.Keys())
+
+	evictedKey, evictedValue, evicted = r.Set("e", 4)
+	assert.Equal(t, "b", evictedKey)
+	assert.Equal(t, 10, evictedValue)
+	assert.True(t, evicted)
+	assert.Equal(t, []string{"c", "d", "e"}, r.Keys())
+	_, present = r.Get("b")
+	assert.False(t, present)
+
+	// deleting makes room
+	oldValue, present := r.Delete("d")
+	assert.Equal(t, 3, oldValue)
+	assert.True(t, present)
+	assert.Equal(t, 2, r.Len())
+	_, _, evicted = r.Set("f", 5)
+	assert.False(t, evicted)
+	assert.Equal(t, []string{"c", "e", "f"}, r.Keys())
+	assert.Equal(t, 3, r.Len())
+
+	assert.Panics(t, func() { NewRing[string, int](0) })
+}
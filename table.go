@@ -0,0 +1,23 @@
+package orderedmap
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteTable writes the ordered map to w as an aligned, human-readable table, with a
+// `KEY VALUE` header followed by one row per pair, from the oldest to the newest.
+// Keys and values are formatted with %v.
+func (om *OrderedMap[K,V]) WriteTable(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(writer, "KEY\tVALUE"); err != nil {
+		return err
+	}
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if _, err := fmt.Fprintf(writer, "%v\t%v\n", pair.Key, pair.Value); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,28 @@
+package orderedmap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTable(t *testing.T) {
+	om := New[string, any]()
+	om.Set("foo", "bar")
+	om.Set("a much longer key", 12)
+	om.Set("list", []int{1, 2})
+
+	var buf bytes.Buffer
+	require.NoError(t, om.WriteTable(&buf))
+	assert.Equal(t, `KEY                VALUE
+foo                bar
+a much longer key  12
+list               [1 2]
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, New[int, int]().WriteTable(&buf))
+	assert.Equal(t, "KEY  VALUE\n", buf.String())
+}
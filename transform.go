@@ -0,0 +1,35 @@
+package orderedmap
+
+// MapValues returns a new ordered map with the same keys, in the same order, and whose values
+// are the result of calling f on each pair. It's a function rather than a method because
+// methods can't have their own type parameters.
+func MapValues[K comparable, V, W any](om *OrderedMap[K,V], f func(key K, value V) W) *OrderedMap[K,W] {
+	mapped := NewWithCapacity[K,W](om.Len())
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		mapped.pushBack(pair.Key, f(pair.Key, pair.Value))
+	}
+	return mapped
+}
+
+// FilterMap returns a new ordered map holding, in the same order, the pairs for which f returns
+// true, with the values f returns for them. It's the same as a Filter followed by MapValues,
+// minus the intermediate map.
+func FilterMap[K comparable, V, W any](om *OrderedMap[K,V], f func(key K, value V) (W, bool)) *OrderedMap[K,W] {
+	mapped := New[K,W]()
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		if value, keep := f(pair.Key, pair.Value); keep {
+			mapped.pushBack(pair.Key, value)
+		}
+	}
+	return mapped
+}
+
+// Fold reduces the ordered map to a single value, by successively calling f on each pair,
+// from the oldest to the newest, with the accumulated value so far, starting with init.
+func Fold[K comparable, V, A any](om *OrderedMap[K,V], init A, f func(acc A, key K, value V) A) A {
+	acc := init
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		acc = f(acc, pair.Key, pair.Value)
+	}
+	return acc
+}
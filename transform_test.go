@@ -0,0 +1,64 @@
+package orderedmap
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapValues(t *testing.T) {
+	om := New[string, string]()
+	om.Set("foo", "12")
+	om.Set("bar", "-3")
+	om.Set("baz", "not a number")
+
+	mapped := MapValues(om, func(_ string, value string) int {
+		n, _ := strconv.Atoi(value)
+		return n
+	})
+	assertOrderedPairsEqual[string, int](t, mapped, []string{"foo", "bar", "baz"}, []int{12, -3, 0})
+	assertLenEqual(t, om, 3)
+	assert.Equal(t, []string{"12", "-3", "not a number"}, om.Values())
+
+	assertLenEqual(t, MapValues(New[int, int](), func(int, int) bool { return true }), 0)
+}
+
+func TestFilterMap(t *testing.T) {
+	om := New[string, string]()
+	om.Set("foo", "12")
+	om.Set("bar", "not a number")
+	om.Set("baz", "-3")
+
+	mapped := FilterMap(om, func(_ string, value string) (int, bool) {
+		n, err := strconv.Atoi(value)
+		return n, err == nil
+	})
+	assertOrderedPairsEqual[string, int](t, mapped, []string{"foo", "baz"}, []int{12, -3})
+	assertLenEqual(t, om, 3)
+
+	none := FilterMap(om, func(string, string) (int, bool) { return 0, false })
+	assertLenEqual(t, none, 0)
+}
+
+func TestFold(t *testing.T) {
+	om := New[string, int]()
+	om.Set("foo", 1)
+	om.Set("bar", 2)
+	om.Set("baz", 3)
+
+	sum := Fold(om, 0, func(acc int, _ string, value int) int {
+		return acc + value
+	})
+	assert.Equal(t, 6, sum)
+
+	joined := Fold(om, "", func(acc string, key string, value int) string {
+		if acc != "" {
+			acc += ","
+		}
+		return acc + key + "=" + strconv.Itoa(value)
+	})
+	assert.Equal(t, "foo=1,bar=2,baz=3", joined)
+
+	assert.Equal(t, 12, Fold(New[string, int](), 12, func(int, string, int) int { return 0 }))
+}
@@ -0,0 +1,65 @@
+package orderedmap
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+var (
+	_ xml.Marshaler = &OrderedMap[string,any]{}
+)
+
+// MarshalXML implements the xml.Marshaler interface.
+// Pairs are emitted, from the oldest to the newest, as `<entry key="...">value</entry>` child elements.
+func (om *OrderedMap[K,V]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// when not given a name, encoding/xml names the element after the type, whose type parameters
+	// would make for an invalid XML name
+	if i := strings.IndexByte(start.Name.Local, '['); i >= 0 {
+		start.Name.Local = start.Name.Local[:i]
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		key, err := encodeKey(pair.Key)
+		if err != nil {
+			return err
+		}
+
+		entry := xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+		}
+		if isNilXMLValue(reflect.ValueOf(&pair.Value)) {
+			// encoding/xml would skip the entry altogether, losing the key
+			if err := e.EncodeToken(entry); err != nil {
+				return err
+			}
+			if err := e.EncodeToken(entry.End()); err != nil {
+				return err
+			}
+			continue
+		}
+		// see EncodeJSON for why a pointer to the value is encoded
+		if err := e.EncodeElement(&pair.Value, entry); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// isNilXMLValue returns whether the value, once pointers and interfaces are followed, is nil,
+// which encoding/xml encodes as nothing at all.
+func isNilXMLValue(value reflect.Value) bool {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package orderedmap
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customXMLValue string
+
+func (v *pointerMarshaler) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement("custom:"+string(*v), start)
+}
+
+func (v customXMLValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement("custom:"+string(v), start)
+}
+
+func TestMarshalXML(t *testing.T) {
+	t.Run("top level", func(t *testing.T) {
+		om := New[string, any]()
+		om.Set("foo", "bar")
+		om.Set("an", 78)
+		om.Set("escaped", "<&>")
+
+		b, err := xml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `<OrderedMap><entry key="foo">bar</entry><entry key="an">78</entry><entry key="escaped">&lt;&amp;&gt;</entry></OrderedMap>`, string(b))
+	})
+
+	t.Run("as a struct field", func(t *testing.T) {
+		type wrapper struct {
+			XMLName xml.Name                  `xml:"config"`
+			Map     *OrderedMap[int, string] `xml:"settings"`
+		}
+
+		om := New[int, string]()
+		om.Set(12, "foo")
+		om.Set(1, "bar")
+
+		b, err := xml.Marshal(wrapper{Map: om})
+		require.NoError(t, err)
+		assert.Equal(t, `<config><settings><entry key="12">foo</entry><entry key="1">bar</entry></settings></config>`, string(b))
+	})
+
+	t.Run("values implementing xml.Marshaler", func(t *testing.T) {
+		om := New[string, customXMLValue]()
+		om.Set("foo", "bar")
+
+		b, err := xml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `<OrderedMap><entry key="foo">custom:bar</entry></OrderedMap>`, string(b))
+	})
+
+	t.Run("struct values", func(t *testing.T) {
+		type point struct {
+			X int `xml:"x,attr"`
+			Y int `xml:"y"`
+		}
+
+		om := New[string, point]()
+		om.Set("origin", point{0, 0})
+		om.Set("p", point{1, 2})
+
+		b, err := xml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `<OrderedMap><entry key="origin" x="0"><y>0</y></entry><entry key="p" x="1"><y>2</y></entry></OrderedMap>`, string(b))
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var s struct {
+			XMLName xml.Name                `xml:"config"`
+			Map     OrderedMap[string, int] `xml:"settings"`
+		}
+		b, err := xml.Marshal(&s)
+		require.NoError(t, err)
+		assert.Equal(t, `<config><settings></settings></config>`, string(b))
+	})
+
+	t.Run("nil values", func(t *testing.T) {
+		om := New[string, any]()
+		om.Set("a", nil)
+		om.Set("b", 1)
+		om.Set("c", (*int)(nil))
+
+		b, err := xml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `<OrderedMap><entry key="a"></entry><entry key="b">1</entry><entry key="c"></entry></OrderedMap>`, string(b))
+	})
+
+	t.Run("values with pointer-receiver marshalers", func(t *testing.T) {
+		assertNestedValuesMarshal(t, xml.Marshal, `<OrderedMap><entry key="x">custom:foo</entry></OrderedMap>`)
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		b, err := xml.Marshal(New[string, string]())
+		require.NoError(t, err)
+		assert.Equal(t, `<OrderedMap></OrderedMap>`, string(b))
+	})
+}
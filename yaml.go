@@ -0,0 +1,142 @@
+package orderedmap
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ yaml.Marshaler   = &OrderedMap[int,any]{}
+	_ yaml.Unmarshaler = &OrderedMap[int,any]{}
+)
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// Pairs are emitted as a YAML mapping, from the oldest to the newest.
+func (om *OrderedMap[K,V]) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+	}
+
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		keyNode, err := encodeYAMLNode(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+		// see EncodeJSON for why a pointer to the value is encoded
+		valueNode, err := encodeYAMLNode(&pair.Value)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+
+	return node, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The node must be a YAML mapping; its pairs are set in the order in which they appear in the document.
+// Keys must be unique, and merge keys are expanded in place, with explicit keys taking precedence.
+func (om *OrderedMap[K,V]) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("orderedmap: expected a YAML mapping, got %s at line %d", value.ShortTag(), value.Line)
+	}
+
+	keys := make([]K, len(value.Content)/2)
+	lines := make(map[K]int, len(keys))
+	mergeLine := 0
+	for i := range keys {
+		keyNode := value.Content[2*i]
+		if isYAMLMergeKey(keyNode) {
+			if mergeLine != 0 {
+				return fmt.Errorf("orderedmap: line %d: merge key already defined at line %d", keyNode.Line, mergeLine)
+			}
+			mergeLine = keyNode.Line
+			continue
+		}
+		if err := keyNode.Decode(&keys[i]); err != nil {
+			return err
+		}
+		// when K is an interface, complex keys such as sequences decode to values that can't be map keys
+		if key := reflect.ValueOf(&keys[i]).Elem(); !key.Comparable() {
+			return fmt.Errorf("orderedmap: line %d: invalid map key %#v", keyNode.Line, keys[i])
+		}
+		if line, present := lines[keys[i]]; present {
+			return fmt.Errorf("orderedmap: line %d: mapping key %q already defined at line %d", keyNode.Line, keyNode.Value, line)
+		}
+		lines[keys[i]] = keyNode.Line
+	}
+
+	om.initialize()
+	for i, key := range keys {
+		if isYAMLMergeKey(value.Content[2*i]) {
+			if err := om.mergeYAML(value.Content[2*i+1], lines); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var val V
+		if err := value.Content[2*i+1].Decode(&val); err != nil {
+			return err
+		}
+		om.Set(key, val)
+	}
+
+	return nil
+}
+
+// mergeYAML sets the pairs from the mapping, or sequence of mappings, that a merge key refers to,
+// skipping keys from explicit. When merging several mappings, the earlier ones take precedence.
+func (om *OrderedMap[K,V]) mergeYAML(value *yaml.Node, explicit map[K]int) error {
+	sources := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		sources = value.Content
+	}
+
+	done := make(map[K]bool)
+
+	for _, source := range sources {
+		if source.Kind == yaml.AliasNode {
+			source = source.Alias
+		}
+		if source == nil || source.Kind != yaml.MappingNode {
+			return fmt.Errorf("orderedmap: line %d: merge keys must refer to a mapping or a sequence of mappings", value.Line)
+		}
+
+		var merged OrderedMap[K,V]
+		if err := merged.UnmarshalYAML(source); err != nil {
+			return err
+		}
+		for pair := merged.Oldest(); pair != nil; pair = pair.Next() {
+			if _, present := explicit[pair.Key]; present || done[pair.Key] {
+				continue
+			}
+			om.Set(pair.Key, pair.Value)
+			done[pair.Key] = true
+		}
+	}
+
+	return nil
+}
+
+// isYAMLMergeKey returns whether the given mapping key is a merge key.
+func isYAMLMergeKey(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!merge"
+}
+
+// encodeYAMLNode converts the given value to a yaml.Node, going through its YAML representation.
+func encodeYAMLNode(v any) (*yaml.Node, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document.Content[0], nil
+}
@@ -0,0 +1,176 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	t.Run("preserves insertion order", func(t *testing.T) {
+		om := New[string, any]()
+		om.Set("foo", "bar")
+		om.Set("an", 78)
+		om.Set("nested", map[string]int{"x": 1})
+		om.Set("nothing", nil)
+
+		b, err := yaml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, `foo: bar
+an: 78
+nested:
+    x: 1
+nothing: null
+`, string(b))
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		om := New[int, string]()
+		om.Set(12, "foo")
+		om.Set(1, "bar")
+
+		b, err := yaml.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, "12: foo\n1: bar\n", string(b))
+	})
+
+	t.Run("values with pointer-receiver marshalers", func(t *testing.T) {
+		assertNestedValuesMarshal(t, yaml.Marshal, "x: custom:foo\n")
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var s struct {
+			Map OrderedMap[string, int] `yaml:"map"`
+		}
+		b, err := yaml.Marshal(&s)
+		require.NoError(t, err)
+		assert.Equal(t, "map: {}\n", string(b))
+
+		var decoded struct {
+			Map *OrderedMap[string, int] `yaml:"map"`
+		}
+		require.NoError(t, yaml.Unmarshal(b, &decoded))
+		if assert.NotNil(t, decoded.Map) {
+			assertLenEqual(t, decoded.Map, 0)
+		}
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		b, err := yaml.Marshal(New[string, string]())
+		require.NoError(t, err)
+		assert.Equal(t, "{}\n", string(b))
+	})
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	t.Run("preserves the document's order", func(t *testing.T) {
+		data := `
+foo: bar
+an: 78
+nested:
+  z: 1
+  a: 2
+nothing: null
+`
+		om := New[string, any]()
+		require.NoError(t, yaml.Unmarshal([]byte(data), om))
+
+		assert.Equal(t, []string{"foo", "an", "nested", "nothing"}, om.Keys())
+		assert.Equal(t, []any{"bar", 78, map[string]any{"z": 1, "a": 2}, nil}, om.Values())
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		om := New[int, string]()
+		require.NoError(t, yaml.Unmarshal([]byte("12: foo\n1: bar\n"), om))
+
+		assertOrderedPairsEqual[int, string](t, om, []int{12, 1}, []string{"foo", "bar"})
+	})
+
+	t.Run("into a zero value", func(t *testing.T) {
+		var s struct {
+			Map *OrderedMap[string, int] `yaml:"map"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte("map:\n  b: 2\n  a: 1\n"), &s))
+
+		if assert.NotNil(t, s.Map) {
+			assertOrderedPairsEqual[string, int](t, s.Map, []string{"b", "a"}, []int{2, 1})
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		om := New[string, int]()
+		om.Set("z", 26)
+		om.Set("a", 1)
+		om.Set("m", 13)
+
+		b, err := yaml.Marshal(om)
+		require.NoError(t, err)
+
+		decoded := New[string, int]()
+		require.NoError(t, yaml.Unmarshal(b, decoded))
+		assertOrderedPairsEqual[string, int](t, decoded, []string{"z", "a", "m"}, []int{26, 1, 13})
+	})
+
+	t.Run("merge keys", func(t *testing.T) {
+		data := `
+base: &base
+  b: 2
+  a: 1
+other: &other
+  a: 10
+  c: 3
+single:
+  x: 0
+  <<: *base
+  y: 4
+  a: 5
+several:
+  <<: [*other, *base]
+  d: 6
+inline:
+  <<: {z: 26}
+`
+		om := New[string, *OrderedMap[string, int]]()
+		require.NoError(t, yaml.Unmarshal([]byte(data), om))
+
+		// explicit keys take precedence, and keep their position
+		assertOrderedPairsEqual[string, int](t, om.MustGet("single"), []string{"x", "b", "y", "a"}, []int{0, 2, 4, 5})
+		// earlier merged mappings take precedence
+		assertOrderedPairsEqual[string, int](t, om.MustGet("several"), []string{"a", "c", "b", "d"}, []int{10, 3, 2, 6})
+		assertOrderedPairsEqual[string, int](t, om.MustGet("inline"), []string{"z"}, []int{26})
+
+		err := yaml.Unmarshal([]byte("<<: 12\n"), New[string, int]())
+		assertErrorContains(t, err, "merge keys must refer to a mapping")
+		err = yaml.Unmarshal([]byte("base: &base {a: 1}\nm:\n  <<: *base\n  <<: *base\n"), New[string, *OrderedMap[string, int]]())
+		assertErrorContains(t, err, "line 4: merge key already defined at line 3")
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		err := yaml.Unmarshal([]byte("a: 1\nb: 2\na: 3\n"), New[string, int]())
+		assertErrorContains(t, err, `orderedmap: line 3: mapping key "a" already defined at line 1`)
+
+		// complex keys can't be set in a map
+		err = yaml.Unmarshal([]byte("? [a, b]\n: 1\n"), New[any, int]())
+		assertErrorContains(t, err, `orderedmap: line 1: invalid map key []interface {}{"a", "b"}`)
+		err = yaml.Unmarshal([]byte("? {a: 1}\n: 1\n"), New[any, int]())
+		assertErrorContains(t, err, "invalid map key")
+
+		// keys are compared once decoded
+		err = yaml.Unmarshal([]byte("1: foo\n0x1: bar\n"), New[int, string]())
+		assertErrorContains(t, err, "already defined")
+	})
+
+	t.Run("not a mapping", func(t *testing.T) {
+		om := New[string, int]()
+		err := yaml.Unmarshal([]byte("- 1\n- 2\n"), om)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "expected a YAML mapping")
+		}
+	})
+}
+
+func (v *pointerMarshaler) MarshalYAML() (interface{}, error) {
+	return "custom:" + string(*v), nil
+}